@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"time"
 
 	"github.com/caarlos0/env/v11"
@@ -14,7 +15,7 @@ type Config struct {
 	Port     string `env:"PORT" envDefault:"8080"`
 	LogLevel string `env:"LOG_LEVEL" envDefault:"info"`
 
-	// API Security
+	// API Security, the master key can reach every guild and issue guild scoped keys
 	APIKey string `env:"API_KEY,required"`
 
 	// External Services
@@ -23,6 +24,10 @@ type Config struct {
 		Timeout time.Duration `env:"WOM_TIMEOUT" envDefault:"30s"`
 	}
 
+	// Deleted records can be restored until they are older than the retention
+	RecordRetention     time.Duration `env:"RECORD_RETENTION" envDefault:"720h"`
+	RecordPurgeInterval time.Duration `env:"RECORD_PURGE_INTERVAL" envDefault:"1h"`
+
 	// Railway detection (for logging format)
 	RailwayProjectID string `env:"RAILWAY_PROJECT_ID"`
 
@@ -36,5 +41,12 @@ func LoadConfig() (*Config, error) {
 		return nil, err
 	}
 
+	if cfg.RecordRetention <= 0 {
+		return nil, errors.New("RECORD_RETENTION must be positive")
+	}
+	if cfg.RecordPurgeInterval <= 0 {
+		return nil, errors.New("RECORD_PURGE_INTERVAL must be positive")
+	}
+
 	return &cfg, nil
 }
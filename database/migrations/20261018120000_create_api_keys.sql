@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE "public"."api_keys" (
+    "key_id" serial NOT NULL,
+    "guild_id" character varying(32) NOT NULL,
+    "name" character varying(64) NOT NULL,
+    "role" character varying(16) NOT NULL,
+    "key_prefix" character varying(16) NOT NULL,
+    "key_hash" character varying(64) NOT NULL,
+    "created_at" timestamp NOT NULL DEFAULT now(),
+    "rotated_at" timestamp,
+    "revoked_at" timestamp,
+    CONSTRAINT "api_keys_pkey" PRIMARY KEY ("key_id"),
+    CONSTRAINT "api_keys_key_hash_key" UNIQUE ("key_hash"),
+    CONSTRAINT "api_keys_role_check" CHECK ("role" IN ('read_only', 'bot', 'admin'))
+) WITH (oids = false);
+
+ALTER TABLE "public"."api_keys"
+ADD CONSTRAINT "api_keys_guild_id_fkey" FOREIGN KEY ("guild_id")
+REFERENCES "guilds" ("guild_id") ON UPDATE CASCADE ON DELETE CASCADE NOT DEFERRABLE;
+
+CREATE INDEX "idx_api_keys_guild_id" ON "api_keys" ("guild_id");
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "api_keys";
+-- +goose StatementEnd
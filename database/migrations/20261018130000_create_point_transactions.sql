@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE "public"."point_transactions" (
+    "transaction_id" serial NOT NULL,
+    "guild_id" character varying(32) NOT NULL,
+    "user_id" character varying(32) NOT NULL,
+    "source" character varying(32) NOT NULL,
+    "delta" integer NOT NULL,
+    "record_id" integer,
+    "event_id" character varying(32),
+    "combat_achievement" character varying(32),
+    "created_at" timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT "point_transactions_pkey" PRIMARY KEY ("transaction_id")
+) WITH (oids = false);
+
+ALTER TABLE "public"."point_transactions"
+ADD CONSTRAINT "point_transactions_user_fkey" FOREIGN KEY ("user_id", "guild_id")
+REFERENCES "users" ("user_id", "guild_id") ON UPDATE CASCADE ON DELETE CASCADE NOT DEFERRABLE;
+
+ALTER TABLE "public"."point_transactions"
+ADD CONSTRAINT "point_transactions_record_id_fkey" FOREIGN KEY ("record_id")
+REFERENCES "records" ("record_id") ON DELETE SET NULL NOT DEFERRABLE;
+
+CREATE INDEX "idx_point_transactions_guild_user" ON "point_transactions" ("guild_id", "user_id", "created_at");
+
+-- Points awarded before the ledger existed are carried over as a single
+-- opening balance so users.points always equals the sum of the ledger
+INSERT INTO "point_transactions" (guild_id, user_id, source, delta)
+SELECT guild_id, user_id, 'opening_balance', points
+FROM users
+WHERE points != 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "point_transactions";
+-- +goose StatementEnd
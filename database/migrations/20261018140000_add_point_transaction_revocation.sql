@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE SEQUENCE "point_transactions_batch_id_seq" INCREMENT 1 MINVALUE 1 MAXVALUE 2147483647 CACHE 1;
+
+ALTER TABLE "point_transactions"
+ADD COLUMN "batch_id" integer,
+ADD COLUMN "reverses_transaction_id" integer,
+ADD COLUMN "revoked_at" timestamp,
+ADD COLUMN "revoked_by" character varying(32),
+ADD COLUMN "revoke_reason" character varying(256);
+
+-- Every existing entry was written by its own call
+UPDATE "point_transactions" SET "batch_id" = nextval('point_transactions_batch_id_seq');
+
+ALTER TABLE "point_transactions"
+ALTER COLUMN "batch_id" SET DEFAULT nextval('point_transactions_batch_id_seq'),
+ALTER COLUMN "batch_id" SET NOT NULL;
+
+ALTER TABLE "point_transactions"
+ADD CONSTRAINT "point_transactions_reverses_transaction_id_fkey" FOREIGN KEY ("reverses_transaction_id")
+REFERENCES "point_transactions" ("transaction_id") ON DELETE CASCADE NOT DEFERRABLE;
+
+ALTER TABLE "point_transactions"
+ADD CONSTRAINT "point_transactions_reverses_transaction_id_key" UNIQUE ("reverses_transaction_id");
+
+CREATE INDEX "idx_point_transactions_batch_id" ON "point_transactions" ("guild_id", "batch_id");
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "point_transactions"
+DROP COLUMN IF EXISTS "batch_id",
+DROP COLUMN IF EXISTS "reverses_transaction_id",
+DROP COLUMN IF EXISTS "revoked_at",
+DROP COLUMN IF EXISTS "revoked_by",
+DROP COLUMN IF EXISTS "revoke_reason";
+
+DROP SEQUENCE IF EXISTS "point_transactions_batch_id_seq";
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE "public"."guild_multiplier_windows" (
+    "window_id" serial NOT NULL,
+    "guild_id" character varying(32) NOT NULL,
+    "name" character varying(64) NOT NULL,
+    "multiplier" integer NOT NULL,
+    "starts_at" timestamptz NOT NULL,
+    "ends_at" timestamptz NOT NULL,
+    CONSTRAINT "guild_multiplier_windows_pkey" PRIMARY KEY ("window_id"),
+    CONSTRAINT "guild_multiplier_windows_multiplier_check" CHECK ("multiplier" BETWEEN 1 AND 10),
+    CONSTRAINT "guild_multiplier_windows_range_check" CHECK ("ends_at" > "starts_at")
+) WITH (oids = false);
+
+ALTER TABLE "public"."guild_multiplier_windows"
+ADD CONSTRAINT "guild_multiplier_windows_guild_id_fkey" FOREIGN KEY ("guild_id")
+REFERENCES "guilds" ("guild_id") ON UPDATE CASCADE ON DELETE CASCADE NOT DEFERRABLE;
+
+CREATE INDEX "idx_guild_multiplier_windows_guild_id" ON "guild_multiplier_windows" ("guild_id", "ends_at");
+
+-- Ledger entries keep the multiplier they were awarded with, delta is
+-- always the multiplied amount
+ALTER TABLE "point_transactions" ADD COLUMN "multiplier" integer NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "point_transactions" DROP COLUMN IF EXISTS "multiplier";
+DROP TABLE IF EXISTS "guild_multiplier_windows";
+-- +goose StatementEnd
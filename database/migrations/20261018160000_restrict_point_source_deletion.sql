@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Combat achievements used to be removed along with their point source,
+-- deleting a point source that's still in use should fail instead. The
+-- guild foreign key keeps guild deletion cascading to combat achievements.
+ALTER TABLE "combat_achievement"
+DROP CONSTRAINT IF EXISTS "combat_achievement_guild_id_point_source_fkey";
+
+ALTER TABLE "combat_achievement"
+ADD CONSTRAINT "combat_achievement_guild_id_point_source_fkey" FOREIGN KEY ("guild_id", "point_source")
+REFERENCES "point_sources" ("guild_id", "source") ON UPDATE CASCADE ON DELETE NO ACTION NOT DEFERRABLE;
+
+ALTER TABLE "combat_achievement"
+ADD CONSTRAINT "combat_achievement_guild_id_fkey" FOREIGN KEY ("guild_id")
+REFERENCES "guilds" ("guild_id") ON UPDATE CASCADE ON DELETE CASCADE NOT DEFERRABLE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "combat_achievement"
+DROP CONSTRAINT IF EXISTS "combat_achievement_guild_id_fkey";
+
+ALTER TABLE "combat_achievement"
+DROP CONSTRAINT IF EXISTS "combat_achievement_guild_id_point_source_fkey";
+
+ALTER TABLE "combat_achievement"
+ADD CONSTRAINT "combat_achievement_guild_id_point_source_fkey" FOREIGN KEY ("guild_id", "point_source")
+REFERENCES "point_sources" ("guild_id", "source") ON UPDATE CASCADE ON DELETE CASCADE NOT DEFERRABLE;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Records submitted while a guild has a mod channel wait in a queue until a
+-- moderator reviews them, only approved records are ranked
+ALTER TABLE "records"
+ADD COLUMN "status" character varying(16) NOT NULL DEFAULT 'approved',
+ADD COLUMN "submitted_by" character varying(32),
+ADD COLUMN "reviewed_by" character varying(32),
+ADD COLUMN "reviewed_at" timestamp,
+ADD COLUMN "review_reason" character varying(256);
+
+ALTER TABLE "records"
+ADD CONSTRAINT "records_status_check" CHECK ("status" IN ('pending', 'approved', 'rejected'));
+
+CREATE INDEX "idx_records_guild_pending" ON "records" ("guild_id", "date") WHERE "status" = 'pending';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS "idx_records_guild_pending";
+
+ALTER TABLE "records"
+DROP CONSTRAINT IF EXISTS "records_status_check",
+DROP COLUMN IF EXISTS "status",
+DROP COLUMN IF EXISTS "submitted_by",
+DROP COLUMN IF EXISTS "reviewed_by",
+DROP COLUMN IF EXISTS "reviewed_at",
+DROP COLUMN IF EXISTS "review_reason";
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE "public"."record_evidence" (
+    "evidence_id" serial NOT NULL,
+    "record_id" integer NOT NULL,
+    "guild_id" character varying(32) NOT NULL,
+    "kind" character varying(16) NOT NULL,
+    "url" character varying(512) NOT NULL,
+    "submitted_by" character varying(32) NOT NULL,
+    "created_at" timestamp NOT NULL DEFAULT now(),
+    CONSTRAINT "record_evidence_pkey" PRIMARY KEY ("evidence_id"),
+    CONSTRAINT "record_evidence_kind_check" CHECK ("kind" IN ('screenshot', 'video', 'message'))
+) WITH (oids = false);
+
+ALTER TABLE "public"."record_evidence"
+ADD CONSTRAINT "record_evidence_record_id_fkey" FOREIGN KEY ("record_id")
+REFERENCES "records" ("record_id") ON UPDATE CASCADE ON DELETE CASCADE NOT DEFERRABLE;
+
+ALTER TABLE "public"."record_evidence"
+ADD CONSTRAINT "record_evidence_guild_id_fkey" FOREIGN KEY ("guild_id")
+REFERENCES "guilds" ("guild_id") ON UPDATE CASCADE ON DELETE CASCADE NOT DEFERRABLE;
+
+CREATE INDEX "idx_record_evidence_record_id" ON "record_evidence" ("record_id");
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "record_evidence";
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Backdated records can't predate the boss, bosses without a known release
+-- date accept any date in the past
+ALTER TABLE "bosses"
+ADD COLUMN "released_at" date;
+
+UPDATE "bosses" SET "released_at" = '2024-06-05' WHERE "name" = 'araxxor';
+UPDATE "bosses" SET "released_at" = '2024-11-20' WHERE "name" IN ('royal_titans_1', 'royal_titans_2');
+UPDATE "bosses" SET "released_at" = '2025-05-14' WHERE "name" IN ('yama_1', 'yama_2');
+UPDATE "bosses" SET "released_at" = '2025-07-23' WHERE "name" IN ('doom_of_mokhaiotl', 'doom_of_mokhaiotl_depth');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "bosses"
+DROP COLUMN IF EXISTS "released_at";
+-- +goose StatementEnd
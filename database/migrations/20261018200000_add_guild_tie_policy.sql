@@ -0,0 +1,61 @@
+-- +goose Up
+-- +goose StatementBegin
+-- How a guild ranks equal values: 'earliest' gives the earlier achiever the
+-- higher position, 'shared' gives every tied record the same position
+ALTER TABLE "guilds"
+ADD COLUMN "tie_policy" character varying(16) NOT NULL DEFAULT 'earliest',
+ADD CONSTRAINT "guilds_tie_policy_check" CHECK ("tie_policy" IN ('earliest', 'shared'));
+
+-- Every approved record that takes part in a ranking with its position, team
+-- bosses rank every record and solo bosses each user's best one
+CREATE VIEW "record_rankings" AS
+WITH eligible AS (
+    SELECT r.record_id, r.guild_id, r.boss_name, r.value, r.date, vt.higher_is_better
+    FROM records r
+    JOIN bosses b ON r.boss_name = b.name
+    JOIN value_types vt ON b.value_type = vt.name
+    WHERE b.solo = false AND r.status = 'approved'
+
+    UNION ALL
+
+    SELECT s.record_id, s.guild_id, s.boss_name, s.value, s.date, s.higher_is_better
+    FROM (
+        SELECT DISTINCT ON (r.guild_id, r.boss_name, tm.user_id)
+            r.record_id, r.guild_id, r.boss_name, r.value, r.date, vt.higher_is_better
+        FROM records r
+        JOIN teams tm ON r.record_id = tm.record_id AND r.guild_id = tm.guild_id
+        JOIN bosses b ON r.boss_name = b.name
+        JOIN value_types vt ON b.value_type = vt.name
+        WHERE b.solo = true AND r.status = 'approved'
+        ORDER BY r.guild_id, r.boss_name, tm.user_id,
+                 CASE WHEN vt.higher_is_better THEN -r.value ELSE r.value END ASC, r.date ASC, r.record_id ASC
+    ) s
+)
+SELECT
+    e.record_id,
+    e.guild_id,
+    e.boss_name,
+    e.value,
+    e.date,
+    (CASE WHEN g.tie_policy = 'shared'
+        THEN RANK() OVER (
+            PARTITION BY e.guild_id, e.boss_name
+            ORDER BY CASE WHEN e.higher_is_better THEN -e.value ELSE e.value END ASC
+        )
+        ELSE ROW_NUMBER() OVER (
+            PARTITION BY e.guild_id, e.boss_name
+            ORDER BY CASE WHEN e.higher_is_better THEN -e.value ELSE e.value END ASC, e.date ASC, e.record_id ASC
+        )
+    END)::bigint AS position
+FROM eligible e
+JOIN guilds g ON e.guild_id = g.guild_id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP VIEW IF EXISTS "record_rankings";
+
+ALTER TABLE "guilds"
+DROP CONSTRAINT IF EXISTS "guilds_tie_policy_check",
+DROP COLUMN IF EXISTS "tie_policy";
+-- +goose StatementEnd
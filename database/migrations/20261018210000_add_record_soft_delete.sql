@@ -0,0 +1,103 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Deleting a record only marks it, it can be restored with its team until the
+-- retention job purges it
+ALTER TABLE "records"
+ADD COLUMN "deleted_at" timestamp,
+ADD COLUMN "deleted_by" character varying(32);
+
+CREATE INDEX "idx_records_guild_deleted" ON "records" ("guild_id", "deleted_at") WHERE "deleted_at" IS NOT NULL;
+
+CREATE OR REPLACE VIEW "record_rankings" AS
+WITH eligible AS (
+    SELECT r.record_id, r.guild_id, r.boss_name, r.value, r.date, vt.higher_is_better
+    FROM records r
+    JOIN bosses b ON r.boss_name = b.name
+    JOIN value_types vt ON b.value_type = vt.name
+    WHERE b.solo = false AND r.status = 'approved' AND r.deleted_at IS NULL
+
+    UNION ALL
+
+    SELECT s.record_id, s.guild_id, s.boss_name, s.value, s.date, s.higher_is_better
+    FROM (
+        SELECT DISTINCT ON (r.guild_id, r.boss_name, tm.user_id)
+            r.record_id, r.guild_id, r.boss_name, r.value, r.date, vt.higher_is_better
+        FROM records r
+        JOIN teams tm ON r.record_id = tm.record_id AND r.guild_id = tm.guild_id
+        JOIN bosses b ON r.boss_name = b.name
+        JOIN value_types vt ON b.value_type = vt.name
+        WHERE b.solo = true AND r.status = 'approved' AND r.deleted_at IS NULL
+        ORDER BY r.guild_id, r.boss_name, tm.user_id,
+                 CASE WHEN vt.higher_is_better THEN -r.value ELSE r.value END ASC, r.date ASC, r.record_id ASC
+    ) s
+)
+SELECT
+    e.record_id,
+    e.guild_id,
+    e.boss_name,
+    e.value,
+    e.date,
+    (CASE WHEN g.tie_policy = 'shared'
+        THEN RANK() OVER (
+            PARTITION BY e.guild_id, e.boss_name
+            ORDER BY CASE WHEN e.higher_is_better THEN -e.value ELSE e.value END ASC
+        )
+        ELSE ROW_NUMBER() OVER (
+            PARTITION BY e.guild_id, e.boss_name
+            ORDER BY CASE WHEN e.higher_is_better THEN -e.value ELSE e.value END ASC, e.date ASC, e.record_id ASC
+        )
+    END)::bigint AS position
+FROM eligible e
+JOIN guilds g ON e.guild_id = g.guild_id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE VIEW "record_rankings" AS
+WITH eligible AS (
+    SELECT r.record_id, r.guild_id, r.boss_name, r.value, r.date, vt.higher_is_better
+    FROM records r
+    JOIN bosses b ON r.boss_name = b.name
+    JOIN value_types vt ON b.value_type = vt.name
+    WHERE b.solo = false AND r.status = 'approved'
+
+    UNION ALL
+
+    SELECT s.record_id, s.guild_id, s.boss_name, s.value, s.date, s.higher_is_better
+    FROM (
+        SELECT DISTINCT ON (r.guild_id, r.boss_name, tm.user_id)
+            r.record_id, r.guild_id, r.boss_name, r.value, r.date, vt.higher_is_better
+        FROM records r
+        JOIN teams tm ON r.record_id = tm.record_id AND r.guild_id = tm.guild_id
+        JOIN bosses b ON r.boss_name = b.name
+        JOIN value_types vt ON b.value_type = vt.name
+        WHERE b.solo = true AND r.status = 'approved'
+        ORDER BY r.guild_id, r.boss_name, tm.user_id,
+                 CASE WHEN vt.higher_is_better THEN -r.value ELSE r.value END ASC, r.date ASC, r.record_id ASC
+    ) s
+)
+SELECT
+    e.record_id,
+    e.guild_id,
+    e.boss_name,
+    e.value,
+    e.date,
+    (CASE WHEN g.tie_policy = 'shared'
+        THEN RANK() OVER (
+            PARTITION BY e.guild_id, e.boss_name
+            ORDER BY CASE WHEN e.higher_is_better THEN -e.value ELSE e.value END ASC
+        )
+        ELSE ROW_NUMBER() OVER (
+            PARTITION BY e.guild_id, e.boss_name
+            ORDER BY CASE WHEN e.higher_is_better THEN -e.value ELSE e.value END ASC, e.date ASC, e.record_id ASC
+        )
+    END)::bigint AS position
+FROM eligible e
+JOIN guilds g ON e.guild_id = g.guild_id;
+
+DROP INDEX IF EXISTS "idx_records_guild_deleted";
+
+ALTER TABLE "records"
+DROP COLUMN IF EXISTS "deleted_at",
+DROP COLUMN IF EXISTS "deleted_by";
+-- +goose StatementEnd
@@ -0,0 +1,176 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Records and event results belong to a season, closing a season keeps it
+-- readable and opens the next one so a guild always has exactly one open season
+CREATE TABLE "public"."guild_seasons" (
+    "season_id" serial NOT NULL,
+    "guild_id" character varying(32) NOT NULL,
+    "name" character varying(64) NOT NULL,
+    "started_at" timestamp NOT NULL DEFAULT now(),
+    "ended_at" timestamp,
+    CONSTRAINT "guild_seasons_pkey" PRIMARY KEY ("season_id")
+) WITH (oids = false);
+
+ALTER TABLE "public"."guild_seasons"
+ADD CONSTRAINT "guild_seasons_guild_id_fkey" FOREIGN KEY ("guild_id")
+REFERENCES "guilds" ("guild_id") ON UPDATE CASCADE ON DELETE CASCADE NOT DEFERRABLE;
+
+CREATE UNIQUE INDEX "idx_guild_seasons_open" ON "guild_seasons" ("guild_id") WHERE "ended_at" IS NULL;
+
+-- Points are all-time unless the guild opts in, then the leaderboard only
+-- counts points earned during the season
+ALTER TABLE "guilds"
+ADD COLUMN "seasonal_points" boolean NOT NULL DEFAULT false;
+
+-- Everything so far belongs to each guild's first season
+INSERT INTO "guild_seasons" ("guild_id", "name", "started_at")
+SELECT g.guild_id, 'Season 1', LEAST(
+    (SELECT min(r.date) FROM records r WHERE r.guild_id = g.guild_id),
+    (SELECT min(pt.created_at) FROM point_transactions pt WHERE pt.guild_id = g.guild_id),
+    now()
+)
+FROM guilds g;
+
+ALTER TABLE "records" ADD COLUMN "season_id" integer;
+UPDATE "records" r SET "season_id" = s.season_id FROM guild_seasons s WHERE s.guild_id = r.guild_id;
+ALTER TABLE "records" ALTER COLUMN "season_id" SET NOT NULL;
+
+ALTER TABLE "records"
+ADD CONSTRAINT "records_season_id_fkey" FOREIGN KEY ("season_id")
+REFERENCES "guild_seasons" ("season_id") ON DELETE CASCADE NOT DEFERRABLE;
+
+CREATE INDEX "idx_records_guild_season" ON "records" ("guild_id", "season_id", "boss_name");
+
+ALTER TABLE "event" ADD COLUMN "season_id" integer;
+UPDATE "event" e SET "season_id" = s.season_id FROM guild_seasons s WHERE s.guild_id = e.guild_id;
+ALTER TABLE "event" ALTER COLUMN "season_id" SET NOT NULL;
+
+ALTER TABLE "event"
+ADD CONSTRAINT "event_season_id_fkey" FOREIGN KEY ("season_id")
+REFERENCES "guild_seasons" ("season_id") ON DELETE CASCADE NOT DEFERRABLE;
+
+-- Trigger function to open the first season on guild creation
+CREATE OR REPLACE FUNCTION insert_first_guild_season()
+RETURNS TRIGGER AS $$
+BEGIN
+  INSERT INTO guild_seasons (guild_id, name)
+  VALUES (NEW.guild_id, 'Season 1');
+
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER insert_first_guild_season_trigger
+AFTER INSERT ON guilds
+FOR EACH ROW
+EXECUTE FUNCTION insert_first_guild_season();
+
+-- Rankings are kept per season
+CREATE OR REPLACE VIEW "record_rankings" AS
+WITH eligible AS (
+    SELECT r.record_id, r.guild_id, r.boss_name, r.value, r.date, vt.higher_is_better, r.season_id
+    FROM records r
+    JOIN bosses b ON r.boss_name = b.name
+    JOIN value_types vt ON b.value_type = vt.name
+    WHERE b.solo = false AND r.status = 'approved' AND r.deleted_at IS NULL
+
+    UNION ALL
+
+    SELECT s.record_id, s.guild_id, s.boss_name, s.value, s.date, s.higher_is_better, s.season_id
+    FROM (
+        SELECT DISTINCT ON (r.guild_id, r.season_id, r.boss_name, tm.user_id)
+            r.record_id, r.guild_id, r.boss_name, r.value, r.date, vt.higher_is_better, r.season_id
+        FROM records r
+        JOIN teams tm ON r.record_id = tm.record_id AND r.guild_id = tm.guild_id
+        JOIN bosses b ON r.boss_name = b.name
+        JOIN value_types vt ON b.value_type = vt.name
+        WHERE b.solo = true AND r.status = 'approved' AND r.deleted_at IS NULL
+        ORDER BY r.guild_id, r.season_id, r.boss_name, tm.user_id,
+                 CASE WHEN vt.higher_is_better THEN -r.value ELSE r.value END ASC, r.date ASC, r.record_id ASC
+    ) s
+)
+SELECT
+    e.record_id,
+    e.guild_id,
+    e.boss_name,
+    e.value,
+    e.date,
+    (CASE WHEN g.tie_policy = 'shared'
+        THEN RANK() OVER (
+            PARTITION BY e.guild_id, e.season_id, e.boss_name
+            ORDER BY CASE WHEN e.higher_is_better THEN -e.value ELSE e.value END ASC
+        )
+        ELSE ROW_NUMBER() OVER (
+            PARTITION BY e.guild_id, e.season_id, e.boss_name
+            ORDER BY CASE WHEN e.higher_is_better THEN -e.value ELSE e.value END ASC, e.date ASC, e.record_id ASC
+        )
+    END)::bigint AS position,
+    e.season_id
+FROM eligible e
+JOIN guilds g ON e.guild_id = g.guild_id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP VIEW IF EXISTS "record_rankings";
+
+CREATE VIEW "record_rankings" AS
+WITH eligible AS (
+    SELECT r.record_id, r.guild_id, r.boss_name, r.value, r.date, vt.higher_is_better
+    FROM records r
+    JOIN bosses b ON r.boss_name = b.name
+    JOIN value_types vt ON b.value_type = vt.name
+    WHERE b.solo = false AND r.status = 'approved' AND r.deleted_at IS NULL
+
+    UNION ALL
+
+    SELECT s.record_id, s.guild_id, s.boss_name, s.value, s.date, s.higher_is_better
+    FROM (
+        SELECT DISTINCT ON (r.guild_id, r.boss_name, tm.user_id)
+            r.record_id, r.guild_id, r.boss_name, r.value, r.date, vt.higher_is_better
+        FROM records r
+        JOIN teams tm ON r.record_id = tm.record_id AND r.guild_id = tm.guild_id
+        JOIN bosses b ON r.boss_name = b.name
+        JOIN value_types vt ON b.value_type = vt.name
+        WHERE b.solo = true AND r.status = 'approved' AND r.deleted_at IS NULL
+        ORDER BY r.guild_id, r.boss_name, tm.user_id,
+                 CASE WHEN vt.higher_is_better THEN -r.value ELSE r.value END ASC, r.date ASC, r.record_id ASC
+    ) s
+)
+SELECT
+    e.record_id,
+    e.guild_id,
+    e.boss_name,
+    e.value,
+    e.date,
+    (CASE WHEN g.tie_policy = 'shared'
+        THEN RANK() OVER (
+            PARTITION BY e.guild_id, e.boss_name
+            ORDER BY CASE WHEN e.higher_is_better THEN -e.value ELSE e.value END ASC
+        )
+        ELSE ROW_NUMBER() OVER (
+            PARTITION BY e.guild_id, e.boss_name
+            ORDER BY CASE WHEN e.higher_is_better THEN -e.value ELSE e.value END ASC, e.date ASC, e.record_id ASC
+        )
+    END)::bigint AS position
+FROM eligible e
+JOIN guilds g ON e.guild_id = g.guild_id;
+
+DROP TRIGGER IF EXISTS insert_first_guild_season_trigger ON guilds;
+DROP FUNCTION IF EXISTS insert_first_guild_season();
+
+ALTER TABLE "event"
+DROP CONSTRAINT IF EXISTS "event_season_id_fkey",
+DROP COLUMN IF EXISTS "season_id";
+
+DROP INDEX IF EXISTS "idx_records_guild_season";
+
+ALTER TABLE "records"
+DROP CONSTRAINT IF EXISTS "records_season_id_fkey",
+DROP COLUMN IF EXISTS "season_id";
+
+ALTER TABLE "guilds"
+DROP COLUMN IF EXISTS "seasonal_points";
+
+DROP TABLE IF EXISTS "guild_seasons";
+-- +goose StatementEnd
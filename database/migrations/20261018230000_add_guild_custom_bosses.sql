@@ -0,0 +1,233 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Custom bosses and categories live next to the global ones so they rank
+-- through the same queries, a guild_id marks them as owned by one guild.
+-- Names stay the primary key, custom ones are stored as <guild_id>:<name> so
+-- guilds never collide with each other or with the catalog.
+ALTER TABLE "bosses"
+ADD COLUMN "guild_id" character varying(32);
+
+-- Created before the categories key so guild deletion removes custom
+-- bosses before their categories
+ALTER TABLE "bosses"
+ADD CONSTRAINT "bosses_guild_id_fkey" FOREIGN KEY ("guild_id")
+REFERENCES "guilds" ("guild_id") ON UPDATE CASCADE ON DELETE CASCADE NOT DEFERRABLE;
+
+CREATE INDEX "idx_bosses_guild_id" ON "bosses" ("guild_id");
+
+ALTER TABLE "categories"
+ADD COLUMN "guild_id" character varying(32);
+
+ALTER TABLE "categories"
+ADD CONSTRAINT "categories_guild_id_fkey" FOREIGN KEY ("guild_id")
+REFERENCES "guilds" ("guild_id") ON UPDATE CASCADE ON DELETE CASCADE NOT DEFERRABLE;
+
+CREATE INDEX "idx_categories_guild_id" ON "categories" ("guild_id");
+
+-- Room for a guild id and the separator in front of the name, views on
+-- the widened columns have to be dropped while they change
+DROP VIEW IF EXISTS "record_rankings";
+DROP VIEW IF EXISTS detailed_times;
+
+ALTER TABLE "bosses"
+ALTER COLUMN "name" TYPE character varying(65),
+ALTER COLUMN "category" TYPE character varying(97);
+
+ALTER TABLE "categories"
+ALTER COLUMN "name" TYPE character varying(97);
+
+ALTER TABLE "guild_bosses"
+ALTER COLUMN "boss" TYPE character varying(65),
+ALTER COLUMN "category" TYPE character varying(97);
+
+ALTER TABLE "guild_categories"
+ALTER COLUMN "category" TYPE character varying(97);
+
+ALTER TABLE "records"
+ALTER COLUMN "boss_name" TYPE character varying(65);
+
+CREATE VIEW detailed_times AS
+SELECT
+    t.value AS time,
+    t.boss_name,
+    b.display_name,
+    b.category,
+    t.record_id AS run_id,
+    t.date,
+    array_remove(array_agg(dtt), NULL) AS team
+FROM records t
+LEFT JOIN detailed_time_teams dtt ON t.record_id = dtt.run_id
+LEFT JOIN bosses b ON b.name = t.boss_name
+GROUP BY t.value, t.boss_name, b.category, b.display_name, t.record_id, t.date;
+
+CREATE VIEW "record_rankings" AS
+WITH eligible AS (
+    SELECT r.record_id, r.guild_id, r.boss_name, r.value, r.date, vt.higher_is_better, r.season_id
+    FROM records r
+    JOIN bosses b ON r.boss_name = b.name
+    JOIN value_types vt ON b.value_type = vt.name
+    WHERE b.solo = false AND r.status = 'approved' AND r.deleted_at IS NULL
+
+    UNION ALL
+
+    SELECT s.record_id, s.guild_id, s.boss_name, s.value, s.date, s.higher_is_better, s.season_id
+    FROM (
+        SELECT DISTINCT ON (r.guild_id, r.season_id, r.boss_name, tm.user_id)
+            r.record_id, r.guild_id, r.boss_name, r.value, r.date, vt.higher_is_better, r.season_id
+        FROM records r
+        JOIN teams tm ON r.record_id = tm.record_id AND r.guild_id = tm.guild_id
+        JOIN bosses b ON r.boss_name = b.name
+        JOIN value_types vt ON b.value_type = vt.name
+        WHERE b.solo = true AND r.status = 'approved' AND r.deleted_at IS NULL
+        ORDER BY r.guild_id, r.season_id, r.boss_name, tm.user_id,
+                 CASE WHEN vt.higher_is_better THEN -r.value ELSE r.value END ASC, r.date ASC, r.record_id ASC
+    ) s
+)
+SELECT
+    e.record_id,
+    e.guild_id,
+    e.boss_name,
+    e.value,
+    e.date,
+    (CASE WHEN g.tie_policy = 'shared'
+        THEN RANK() OVER (
+            PARTITION BY e.guild_id, e.season_id, e.boss_name
+            ORDER BY CASE WHEN e.higher_is_better THEN -e.value ELSE e.value END ASC
+        )
+        ELSE ROW_NUMBER() OVER (
+            PARTITION BY e.guild_id, e.season_id, e.boss_name
+            ORDER BY CASE WHEN e.higher_is_better THEN -e.value ELSE e.value END ASC, e.date ASC, e.record_id ASC
+        )
+    END)::bigint AS position,
+    e.season_id
+FROM eligible e
+JOIN guilds g ON e.guild_id = g.guild_id;
+
+-- New guilds only start with the global bosses and categories
+CREATE OR REPLACE FUNCTION insert_guild_bosses_and_categories()
+RETURNS TRIGGER AS $$
+BEGIN
+  INSERT INTO guild_categories (guild_id, category)
+  SELECT NEW.guild_id, name
+  FROM categories
+  WHERE guild_id IS NULL;
+
+  INSERT INTO guild_bosses (guild_id, boss, category)
+  SELECT NEW.guild_id, name, category
+  FROM bosses
+  WHERE guild_id IS NULL;
+
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION insert_guild_bosses_and_categories()
+RETURNS TRIGGER AS $$
+BEGIN
+  INSERT INTO guild_categories (guild_id, category)
+  SELECT NEW.guild_id, name
+  FROM categories;
+
+  INSERT INTO guild_bosses (guild_id, boss, category)
+  SELECT NEW.guild_id, name, category
+  FROM bosses;
+
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DELETE FROM "bosses" WHERE "guild_id" IS NOT NULL;
+DELETE FROM "categories" WHERE "guild_id" IS NOT NULL;
+
+DROP VIEW IF EXISTS "record_rankings";
+DROP VIEW IF EXISTS detailed_times;
+
+ALTER TABLE "bosses"
+ALTER COLUMN "name" TYPE character varying(32),
+ALTER COLUMN "category" TYPE character varying(64);
+
+ALTER TABLE "categories"
+ALTER COLUMN "name" TYPE character varying(64);
+
+ALTER TABLE "guild_bosses"
+ALTER COLUMN "boss" TYPE character varying(32),
+ALTER COLUMN "category" TYPE character varying(64);
+
+ALTER TABLE "guild_categories"
+ALTER COLUMN "category" TYPE character varying(64);
+
+ALTER TABLE "records"
+ALTER COLUMN "boss_name" TYPE character varying(32);
+
+CREATE VIEW detailed_times AS
+SELECT
+    t.value AS time,
+    t.boss_name,
+    b.display_name,
+    b.category,
+    t.record_id AS run_id,
+    t.date,
+    array_remove(array_agg(dtt), NULL) AS team
+FROM records t
+LEFT JOIN detailed_time_teams dtt ON t.record_id = dtt.run_id
+LEFT JOIN bosses b ON b.name = t.boss_name
+GROUP BY t.value, t.boss_name, b.category, b.display_name, t.record_id, t.date;
+
+CREATE VIEW "record_rankings" AS
+WITH eligible AS (
+    SELECT r.record_id, r.guild_id, r.boss_name, r.value, r.date, vt.higher_is_better, r.season_id
+    FROM records r
+    JOIN bosses b ON r.boss_name = b.name
+    JOIN value_types vt ON b.value_type = vt.name
+    WHERE b.solo = false AND r.status = 'approved' AND r.deleted_at IS NULL
+
+    UNION ALL
+
+    SELECT s.record_id, s.guild_id, s.boss_name, s.value, s.date, s.higher_is_better, s.season_id
+    FROM (
+        SELECT DISTINCT ON (r.guild_id, r.season_id, r.boss_name, tm.user_id)
+            r.record_id, r.guild_id, r.boss_name, r.value, r.date, vt.higher_is_better, r.season_id
+        FROM records r
+        JOIN teams tm ON r.record_id = tm.record_id AND r.guild_id = tm.guild_id
+        JOIN bosses b ON r.boss_name = b.name
+        JOIN value_types vt ON b.value_type = vt.name
+        WHERE b.solo = true AND r.status = 'approved' AND r.deleted_at IS NULL
+        ORDER BY r.guild_id, r.season_id, r.boss_name, tm.user_id,
+                 CASE WHEN vt.higher_is_better THEN -r.value ELSE r.value END ASC, r.date ASC, r.record_id ASC
+    ) s
+)
+SELECT
+    e.record_id,
+    e.guild_id,
+    e.boss_name,
+    e.value,
+    e.date,
+    (CASE WHEN g.tie_policy = 'shared'
+        THEN RANK() OVER (
+            PARTITION BY e.guild_id, e.season_id, e.boss_name
+            ORDER BY CASE WHEN e.higher_is_better THEN -e.value ELSE e.value END ASC
+        )
+        ELSE ROW_NUMBER() OVER (
+            PARTITION BY e.guild_id, e.season_id, e.boss_name
+            ORDER BY CASE WHEN e.higher_is_better THEN -e.value ELSE e.value END ASC, e.date ASC, e.record_id ASC
+        )
+    END)::bigint AS position,
+    e.season_id
+FROM eligible e
+JOIN guilds g ON e.guild_id = g.guild_id;
+
+DROP INDEX IF EXISTS "idx_categories_guild_id";
+
+ALTER TABLE "categories"
+DROP CONSTRAINT IF EXISTS "categories_guild_id_fkey",
+DROP COLUMN IF EXISTS "guild_id";
+
+DROP INDEX IF EXISTS "idx_bosses_guild_id";
+
+ALTER TABLE "bosses"
+DROP CONSTRAINT IF EXISTS "bosses_guild_id_fkey",
+DROP COLUMN IF EXISTS "guild_id";
+-- +goose StatementEnd
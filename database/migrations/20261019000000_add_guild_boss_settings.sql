@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Guilds can hide bosses and categories they don't track and order them
+-- themselves, a NULL display_order keeps the default ordering
+ALTER TABLE "guild_bosses"
+ADD COLUMN "enabled" boolean DEFAULT true NOT NULL,
+ADD COLUMN "display_order" smallint;
+
+ALTER TABLE "guild_categories"
+ADD COLUMN "enabled" boolean DEFAULT true NOT NULL,
+ADD COLUMN "display_order" smallint;
+
+-- Bosses added by migrations after a guild was created never reached it
+INSERT INTO guild_categories (guild_id, category)
+SELECT g.guild_id, c.name
+FROM guilds g
+CROSS JOIN categories c
+WHERE c.guild_id IS NULL
+ON CONFLICT DO NOTHING;
+
+INSERT INTO guild_bosses (guild_id, boss, category)
+SELECT g.guild_id, b.name, b.category
+FROM guilds g
+CROSS JOIN bosses b
+WHERE b.guild_id IS NULL AND b.category IS NOT NULL
+ON CONFLICT DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "guild_categories"
+DROP COLUMN IF EXISTS "display_order",
+DROP COLUMN IF EXISTS "enabled";
+
+ALTER TABLE "guild_bosses"
+DROP COLUMN IF EXISTS "display_order",
+DROP COLUMN IF EXISTS "enabled";
+-- +goose StatementEnd
@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Retired bosses keep their records but take no new ones, retired bosses and
+-- categories are no longer handed out to guilds
+ALTER TABLE "bosses"
+ADD COLUMN "retired" boolean DEFAULT false NOT NULL;
+
+ALTER TABLE "categories"
+ADD COLUMN "retired" boolean DEFAULT false NOT NULL;
+
+CREATE OR REPLACE FUNCTION insert_guild_bosses_and_categories()
+RETURNS TRIGGER AS $$
+BEGIN
+  INSERT INTO guild_categories (guild_id, category)
+  SELECT NEW.guild_id, name
+  FROM categories
+  WHERE guild_id IS NULL AND NOT retired;
+
+  INSERT INTO guild_bosses (guild_id, boss, category)
+  SELECT NEW.guild_id, name, category
+  FROM bosses
+  WHERE guild_id IS NULL AND NOT retired;
+
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION insert_guild_bosses_and_categories()
+RETURNS TRIGGER AS $$
+BEGIN
+  INSERT INTO guild_categories (guild_id, category)
+  SELECT NEW.guild_id, name
+  FROM categories
+  WHERE guild_id IS NULL;
+
+  INSERT INTO guild_bosses (guild_id, boss, category)
+  SELECT NEW.guild_id, name, category
+  FROM bosses
+  WHERE guild_id IS NULL;
+
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE "categories"
+DROP COLUMN IF EXISTS "retired";
+
+ALTER TABLE "bosses"
+DROP COLUMN IF EXISTS "retired";
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Values are stored as whole steps, the step is what one stored unit is worth
+-- in the display format: milliseconds for ticks_as_time (600 for a game
+-- tick), hundredths of a percent for percentage and 1 for plain integers.
+-- Bounds apply to the stored value.
+ALTER TABLE "value_types"
+ADD COLUMN "unit" character varying(16),
+ADD COLUMN "display_format" character varying(16) DEFAULT 'integer' NOT NULL,
+ADD COLUMN "min_value" integer,
+ADD COLUMN "max_value" integer,
+ADD COLUMN "step" integer DEFAULT 1 NOT NULL,
+ADD CONSTRAINT "value_types_display_format_check" CHECK ("display_format" IN ('ticks_as_time', 'integer', 'percentage')),
+ADD CONSTRAINT "value_types_step_check" CHECK ("step" > 0),
+ADD CONSTRAINT "value_types_bounds_check" CHECK ("min_value" IS NULL OR "max_value" IS NULL OR "min_value" <= "max_value");
+
+UPDATE "value_types"
+SET "display_format" = 'ticks_as_time', "unit" = 'ticks', "step" = 600
+WHERE "name" = 'time';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "value_types"
+DROP CONSTRAINT IF EXISTS "value_types_bounds_check",
+DROP CONSTRAINT IF EXISTS "value_types_step_check",
+DROP CONSTRAINT IF EXISTS "value_types_display_format_check",
+DROP COLUMN IF EXISTS "step",
+DROP COLUMN IF EXISTS "max_value",
+DROP COLUMN IF EXISTS "min_value",
+DROP COLUMN IF EXISTS "display_format",
+DROP COLUMN IF EXISTS "unit";
+-- +goose StatementEnd
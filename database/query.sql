@@ -75,24 +75,131 @@ WHERE source = @event
 AND guild_id = @guild_id;
 
 -- name: UpdatePointsByEvent :many
+-- An active window multiplies on top of the guild multiplier, overlapping
+-- windows don't stack and the highest one applies
 WITH point_value AS (
-    SELECT points
-    FROM point_sources
-    WHERE source = @event
-    AND guild_id = @guild_id
+    SELECT ps.points AS base_points, (g.multiplier * COALESCE((
+        SELECT MAX(w.multiplier)
+        FROM guild_multiplier_windows w
+        WHERE w.guild_id = ps.guild_id
+        AND w.starts_at <= now()
+        AND w.ends_at > now()
+    ), 1))::int AS multiplier
+    FROM point_sources ps
+    JOIN guilds g ON g.guild_id = ps.guild_id
+    WHERE ps.source = @event
+    AND ps.guild_id = @guild_id
+), batch AS (
+    SELECT nextval('point_transactions_batch_id_seq')::int AS batch_id
+), updated AS (
+    UPDATE users
+    SET points = points + (SELECT base_points * multiplier FROM point_value)
+    WHERE user_id = ANY(@user_ids::text[])
+    AND users.guild_id = @guild_id
+    RETURNING user_id, guild_id, points
+), ledger AS (
+    INSERT INTO point_transactions (guild_id, user_id, source, delta, multiplier, batch_id, record_id, event_id, combat_achievement)
+    SELECT guild_id, user_id, @event, (SELECT base_points * multiplier FROM point_value), (SELECT multiplier FROM point_value), (SELECT batch_id FROM batch), sqlc.narg(record_id)::int, sqlc.narg(event_id)::text, sqlc.narg(combat_achievement)::text
+    FROM updated
 )
-UPDATE users
-SET points = points + (SELECT points FROM point_value)
-WHERE user_id = ANY(@user_ids::text[])
-AND users.guild_id = @guild_id
-RETURNING user_id, guild_id, points, (SELECT points FROM point_value) AS given_points;
+SELECT
+    user_id, guild_id, points,
+    (SELECT base_points * multiplier FROM point_value)::int AS given_points,
+    (SELECT base_points FROM point_value)::int AS base_points,
+    (SELECT multiplier FROM point_value)::int AS multiplier,
+    (SELECT batch_id FROM batch) AS batch_id
+FROM updated;
 
 -- name: UpdatePointsCustom :many
-UPDATE users
-SET points = points + @points
-WHERE user_id = ANY(@user_ids::text[])
-AND guild_id = @guild_id
-RETURNING user_id, guild_id, points, @points::int AS given_points;
+WITH batch AS (
+    SELECT nextval('point_transactions_batch_id_seq')::int AS batch_id
+), updated AS (
+    UPDATE users
+    SET points = points + @points
+    WHERE user_id = ANY(@user_ids::text[])
+    AND guild_id = @guild_id
+    RETURNING user_id, guild_id, points
+), ledger AS (
+    INSERT INTO point_transactions (guild_id, user_id, source, delta, batch_id)
+    SELECT guild_id, user_id, 'custom', @points, (SELECT batch_id FROM batch)
+    FROM updated
+)
+SELECT user_id, guild_id, points, @points::int AS given_points, (SELECT batch_id FROM batch) AS batch_id
+FROM updated;
+
+-- name: GetUserPointTransactions :many
+SELECT transaction_id, batch_id, source, delta, multiplier, record_id, event_id, combat_achievement, reverses_transaction_id, created_at, revoked_at, revoked_by, revoke_reason
+FROM point_transactions
+WHERE guild_id = @guild_id
+AND user_id = @user_id
+ORDER BY created_at DESC, transaction_id DESC
+LIMIT @transaction_limit OFFSET @transaction_offset;
+
+-- name: GetRevocablePointTransactions :many
+SELECT transaction_id, user_id, delta, revoked_at
+FROM point_transactions
+WHERE guild_id = @guild_id
+AND reverses_transaction_id IS NULL
+AND (transaction_id = sqlc.narg(transaction_id)::int OR batch_id = sqlc.narg(batch_id)::int)
+ORDER BY transaction_id
+FOR UPDATE;
+
+-- name: RevokePointTransactions :many
+WITH revoked AS (
+    UPDATE point_transactions
+    SET revoked_at = now(),
+        revoked_by = @revoked_by,
+        revoke_reason = @revoke_reason
+    WHERE guild_id = @guild_id
+    AND transaction_id = ANY(@transaction_ids::int[])
+    AND revoked_at IS NULL
+    RETURNING transaction_id, guild_id, user_id, delta
+), batch AS (
+    SELECT nextval('point_transactions_batch_id_seq')::int AS batch_id
+), reversal AS (
+    INSERT INTO point_transactions (guild_id, user_id, source, delta, batch_id, reverses_transaction_id)
+    SELECT guild_id, user_id, 'revoke', -delta, (SELECT batch_id FROM batch), transaction_id
+    FROM revoked
+), totals AS (
+    SELECT user_id, guild_id, SUM(delta)::int AS delta
+    FROM revoked
+    GROUP BY user_id, guild_id
+), updated AS (
+    UPDATE users u
+    SET points = u.points - t.delta
+    FROM totals t
+    WHERE u.user_id = t.user_id
+    AND u.guild_id = t.guild_id
+    RETURNING u.user_id, u.points
+)
+SELECT r.transaction_id, r.user_id, r.delta, up.points
+FROM revoked r
+JOIN updated up ON up.user_id = r.user_id
+ORDER BY r.transaction_id;
+
+-- name: GetPointsLedgerMismatches :many
+SELECT u.user_id, u.points, COALESCE(SUM(pt.delta), 0)::int AS ledger_points
+FROM users u
+LEFT JOIN point_transactions pt ON pt.user_id = u.user_id AND pt.guild_id = u.guild_id
+WHERE u.guild_id = @guild_id
+GROUP BY u.user_id, u.points
+HAVING u.points != COALESCE(SUM(pt.delta), 0);
+
+-- name: RebuildPointsFromLedger :execrows
+UPDATE users u
+SET points = COALESCE((
+    SELECT SUM(pt.delta)
+    FROM point_transactions pt
+    WHERE pt.user_id = u.user_id
+    AND pt.guild_id = u.guild_id
+), 0)
+WHERE u.guild_id = @guild_id
+AND u.points != COALESCE((
+    SELECT SUM(pt.delta)
+    FROM point_transactions pt
+    WHERE pt.user_id = u.user_id
+    AND pt.guild_id = u.guild_id
+), 0);
 
 -- name: GetLeaderboard :many
 SELECT u.user_id, u.guild_id, u.points, json_agg(r) AS rsns
@@ -103,6 +210,27 @@ GROUP BY u.user_id, u.guild_id, u.points
 ORDER BY u.points DESC
 LIMIT @user_limit;
 
+-- name: GetSeasonLeaderboard :many
+-- Points earned while the season was open, reversals count towards the
+-- season of the transaction they reverse
+SELECT u.user_id, u.guild_id, COALESCE(sp.points, 0)::int AS points, json_agg(r) AS rsns
+FROM users u
+JOIN rsn r ON u.user_id = r.user_id AND u.guild_id = r.guild_id
+LEFT JOIN (
+    SELECT pt.user_id, SUM(pt.delta) AS points
+    FROM point_transactions pt
+    LEFT JOIN point_transactions orig ON orig.transaction_id = pt.reverses_transaction_id
+    JOIN guild_seasons s ON s.guild_id = pt.guild_id AND s.season_id = @season_id
+    WHERE pt.guild_id = @guild_id
+    AND COALESCE(orig.created_at, pt.created_at) >= s.started_at
+    AND (s.ended_at IS NULL OR COALESCE(orig.created_at, pt.created_at) < s.ended_at)
+    GROUP BY pt.user_id
+) sp ON sp.user_id = u.user_id
+WHERE u.guild_id = @guild_id
+GROUP BY u.user_id, u.guild_id, sp.points
+ORDER BY points DESC
+LIMIT @user_limit;
+
 -- name: CreateGuild :one
 INSERT INTO guilds (
   guild_id
@@ -117,9 +245,11 @@ WHERE guild_id = $1;
 
 -- name: GetGuild :one
 SELECT
-    guilds.guild_id, guilds.multiplier, guilds.pb_channel_id, guilds.mod_channel_id, guilds.position_count,
+    guilds.guild_id, guilds.multiplier, guilds.pb_channel_id, guilds.mod_channel_id, guilds.position_count, guilds.tie_policy, guilds.seasonal_points,
+    (SELECT season_id FROM guild_seasons WHERE guild_seasons.guild_id = $1 AND ended_at IS NULL)::int as season_id,
     (SELECT count(user_id) FROM users WHERE users.guild_id = $1) as user_count,
-    (SELECT count(record_id) FROM records WHERE records.guild_id = $1) as record_count
+    (SELECT count(record_id) FROM records WHERE records.guild_id = $1 AND records.status = 'approved' AND records.deleted_at IS NULL
+     AND records.season_id = (SELECT season_id FROM guild_seasons WHERE guild_seasons.guild_id = $1 AND ended_at IS NULL)) as record_count
 FROM guilds
 WHERE guilds.guild_id = $1 LIMIT 1;
 
@@ -128,7 +258,9 @@ UPDATE guilds SET
     multiplier = CASE WHEN @multiplier::numeric IS NOT NULL AND @multiplier::numeric != 0 THEN @multiplier::numeric ELSE multiplier END,
     pb_channel_id = CASE WHEN @pb_channel_id::text IS NOT NULL AND @pb_channel_id::text != '' THEN @pb_channel_id::text ELSE pb_channel_id END,
     mod_channel_id = CASE WHEN @mod_channel_id::text IS NOT NULL AND @mod_channel_id::text != '' THEN @mod_channel_id::text ELSE mod_channel_id END,
-    position_count = CASE WHEN @position_count::smallint IS NOT NULL AND @position_count::smallint != 0 THEN @position_count::smallint ELSE position_count END
+    position_count = CASE WHEN @position_count::smallint IS NOT NULL AND @position_count::smallint != 0 THEN @position_count::smallint ELSE position_count END,
+    tie_policy = CASE WHEN @tie_policy::text IS NOT NULL AND @tie_policy::text != '' THEN @tie_policy::text ELSE tie_policy END,
+    seasonal_points = COALESCE(sqlc.narg(seasonal_points), seasonal_points)
 WHERE guild_id = @guild_id RETURNING guild_id, multiplier, pb_channel_id;
 
 -- name: UpdateEvent :one
@@ -164,7 +296,10 @@ VALUES (
      JOIN value_types vt ON b.value_type = vt.name
      WHERE r.guild_id = @guild_id
        AND r.boss_name = @boss_name
-     ORDER BY CASE WHEN vt.higher_is_better THEN -r.value ELSE r.value END ASC
+       AND r.status = 'approved'
+       AND r.deleted_at IS NULL
+       AND r.season_id = (SELECT season_id FROM guild_seasons WHERE guild_id = @guild_id AND ended_at IS NULL)
+     ORDER BY CASE WHEN vt.higher_is_better THEN -r.value ELSE r.value END ASC, r.date ASC, r.record_id ASC
      LIMIT 1),
     @user_id,
     @guild_id
@@ -187,7 +322,10 @@ WHERE record_id = (
     JOIN value_types vt ON b.value_type = vt.name
     WHERE r.guild_id = @guild_id
       AND r.boss_name = @boss_name
-    ORDER BY CASE WHEN vt.higher_is_better THEN -r.value ELSE r.value END ASC
+      AND r.status = 'approved'
+      AND r.deleted_at IS NULL
+      AND r.season_id = (SELECT season_id FROM guild_seasons WHERE guild_id = @guild_id AND ended_at IS NULL)
+    ORDER BY CASE WHEN vt.higher_is_better THEN -r.value ELSE r.value END ASC, r.date ASC, r.record_id ASC
     LIMIT 1
 )
 AND user_id = @user_id
@@ -203,6 +341,11 @@ AND guild_id = @guild_id;
 INSERT INTO teams (record_id, user_id, guild_id)
 SELECT @record_id, unnest(@user_ids::text[]), @guild_id;
 
+-- name: DeleteTeam :exec
+DELETE FROM teams
+WHERE record_id = @record_id
+AND guild_id = @guild_id;
+
 -- ==================== Records ====================
 
 -- name: CreateRecord :one
@@ -210,88 +353,243 @@ INSERT INTO records (
     value,
     boss_name,
     date,
-    guild_id
+    guild_id,
+    status,
+    submitted_by,
+    season_id
 )
 VALUES (
     @value,
     @boss_name,
     @date,
-    @guild_id
-) RETURNING record_id;
+    @guild_id,
+    @status,
+    sqlc.narg(submitted_by),
+    (SELECT season_id FROM guild_seasons WHERE guild_id = @guild_id AND ended_at IS NULL)
+) RETURNING record_id, season_id;
+
+-- name: LockGuildBoss :exec
+SELECT pg_advisory_xact_lock(hashtext(@guild_id::text), hashtext(@boss_name::text));
+
+-- name: GetRecordBoss :one
+SELECT boss_name
+FROM records
+WHERE guild_id = @guild_id AND record_id = @record_id;
+
+-- name: GetRecordForUpdate :one
+SELECT record_id, boss_name, value, date, status, season_id
+FROM records
+WHERE guild_id = @guild_id AND record_id = @record_id AND deleted_at IS NULL
+FOR UPDATE;
+
+-- name: UpdateRecord :one
+UPDATE records
+SET
+    value = COALESCE(sqlc.narg(value), value),
+    date = COALESCE(sqlc.narg(date), date),
+    boss_name = COALESCE(sqlc.narg(boss_name), boss_name)
+WHERE guild_id = @guild_id
+AND record_id = @record_id
+AND deleted_at IS NULL
+RETURNING record_id, boss_name, value, date, status, season_id;
 
 -- name: DeleteRecord :execrows
-DELETE FROM records r
-WHERE r.guild_id = @guild_id AND r.record_id = @record_id;
+UPDATE records r
+SET deleted_at = now(), deleted_by = sqlc.narg(deleted_by)
+WHERE r.guild_id = @guild_id AND r.record_id = @record_id AND r.deleted_at IS NULL;
 
 -- name: DeleteBossRecords :execrows
-DELETE FROM records
-WHERE guild_id = @guild_id AND boss_name = @boss_name;
+UPDATE records
+SET deleted_at = now(), deleted_by = sqlc.narg(deleted_by)
+WHERE guild_id = @guild_id AND boss_name = @boss_name AND deleted_at IS NULL
+AND season_id = (SELECT season_id FROM guild_seasons WHERE guild_id = @guild_id AND ended_at IS NULL);
 
 -- name: DeleteTopRecord :execrows
-DELETE FROM records
+UPDATE records
+SET deleted_at = now(), deleted_by = sqlc.narg(deleted_by)
 WHERE record_id = (
     SELECT r.record_id
     FROM records r
     JOIN bosses b ON r.boss_name = b.name
     JOIN value_types vt ON b.value_type = vt.name
     WHERE r.guild_id = @guild_id AND r.boss_name = @boss_name
-    ORDER BY CASE WHEN vt.higher_is_better THEN -r.value ELSE r.value END ASC
+      AND r.status = 'approved' AND r.deleted_at IS NULL
+      AND r.season_id = (SELECT season_id FROM guild_seasons WHERE guild_id = @guild_id AND ended_at IS NULL)
+    ORDER BY CASE WHEN vt.higher_is_better THEN -r.value ELSE r.value END ASC, r.date ASC, r.record_id ASC
     LIMIT 1
 )
 AND guild_id = @guild_id;
 
+-- name: GetDeletedRecords :many
+SELECT
+    r.record_id,
+    r.boss_name,
+    b.value_type,
+    r.value,
+    r.date,
+    r.status,
+    r.deleted_at,
+    r.deleted_by,
+    ARRAY(
+        SELECT tm.user_id FROM teams tm
+        WHERE tm.record_id = r.record_id AND tm.guild_id = r.guild_id
+        ORDER BY tm.user_id
+    )::text[] AS user_ids
+FROM records r
+JOIN bosses b ON r.boss_name = b.name
+WHERE r.guild_id = @guild_id AND r.deleted_at IS NOT NULL
+ORDER BY r.deleted_at DESC, r.record_id DESC;
+
+-- name: RestoreRecord :one
+UPDATE records r
+SET deleted_at = NULL, deleted_by = NULL
+FROM bosses b
+WHERE r.guild_id = @guild_id
+AND r.record_id = @record_id
+AND r.deleted_at IS NOT NULL
+AND b.name = r.boss_name
+RETURNING r.record_id, r.boss_name, b.value_type, r.value, r.status, r.season_id;
+
+-- name: PurgeDeletedRecords :execrows
+-- The cutoff comes from the database clock that wrote deleted_at
+DELETE FROM records
+WHERE deleted_at < now() - @retention::interval;
+
 -- name: GetBossInfo :one
-SELECT b.name, b.display_name, b.category, b.solo, b.value_type, vt.higher_is_better
+SELECT b.name, b.display_name, b.category, b.solo, b.value_type, vt.higher_is_better, b.released_at, b.retired,
+    vt.display_format, vt.unit, vt.step, vt.min_value, vt.max_value,
+    EXISTS (
+        SELECT 1 FROM guild_bosses gb
+        JOIN guild_categories gc ON gc.guild_id = gb.guild_id AND gc.category = gb.category
+        WHERE gb.boss = b.name AND gb.guild_id = @guild_id::text
+        AND gb.enabled AND gc.enabled
+    ) AS enabled
 FROM bosses b
 JOIN value_types vt ON b.value_type = vt.name
-WHERE b.name = @boss_name;
+WHERE b.name = @boss_name
+AND (b.guild_id IS NULL OR b.guild_id = @guild_id::text);
 
 -- name: GetBossRecords :many
 SELECT r.record_id, r.value, r.boss_name, r.date, r.guild_id, tm.user_id
 FROM records r
 JOIN teams tm ON r.record_id = tm.record_id AND r.guild_id = tm.guild_id
-WHERE r.guild_id = @guild_id AND r.boss_name = @boss_name
+WHERE r.guild_id = @guild_id AND r.boss_name = @boss_name AND r.season_id = @season_id
+AND r.status = 'approved' AND r.deleted_at IS NULL
 ORDER BY r.record_id, tm.user_id;
 
+-- name: GetBossRanking :many
+SELECT
+    rr.record_id,
+    rr.value,
+    rr.date,
+    rr.position,
+    abs(rr.value - FIRST_VALUE(rr.value) OVER (ORDER BY rr.position, rr.date, rr.record_id))::int AS gap,
+    ARRAY(
+        SELECT tm.user_id FROM teams tm
+        WHERE tm.record_id = rr.record_id AND tm.guild_id = @guild_id
+        ORDER BY tm.user_id
+    )::text[] AS user_ids
+FROM record_rankings rr
+WHERE rr.guild_id = @guild_id AND rr.boss_name = @boss_name AND rr.season_id = @season_id
+ORDER BY rr.position, rr.date, rr.record_id
+LIMIT @record_limit OFFSET @record_offset;
+
+-- name: ResolveGuildParticipants :many
+SELECT u.user_id AS participant, u.user_id
+FROM users u
+WHERE u.guild_id = @guild_id AND u.user_id = ANY(@participants::text[])
+UNION
+SELECT lower(r.rsn) AS participant, r.user_id
+FROM rsn r
+WHERE r.guild_id = @guild_id AND lower(r.rsn) = ANY(@participants::text[]);
+
+-- name: GetGuildRecordTeams :many
+SELECT r.boss_name, r.value, array_agg(tm.user_id ORDER BY tm.user_id)::text[] AS user_ids
+FROM records r
+JOIN teams tm ON r.record_id = tm.record_id AND r.guild_id = tm.guild_id
+WHERE r.guild_id = @guild_id
+AND r.boss_name = ANY(@boss_names::text[])
+AND r.status <> 'rejected'
+AND r.deleted_at IS NULL
+AND r.season_id = (SELECT season_id FROM guild_seasons WHERE guild_id = @guild_id AND ended_at IS NULL)
+GROUP BY r.record_id, r.boss_name, r.value;
+
+-- name: GetGuildRecordSettings :one
+SELECT mod_channel_id, position_count, tie_policy FROM guilds WHERE guild_id = @guild_id;
+
+-- name: GetPendingRecords :many
+SELECT r.record_id, r.boss_name, b.value_type, r.value, r.date, r.submitted_by, tm.user_id,
+       (SELECT json_agg(e ORDER BY e.evidence_id) FROM record_evidence e WHERE e.record_id = r.record_id) AS evidence
+FROM records r
+JOIN teams tm ON r.record_id = tm.record_id AND r.guild_id = tm.guild_id
+JOIN bosses b ON r.boss_name = b.name
+WHERE r.guild_id = @guild_id AND r.status = 'pending' AND r.deleted_at IS NULL
+ORDER BY r.date, r.record_id, tm.user_id;
+
+-- name: GetRecord :many
+SELECT
+    r.record_id,
+    r.boss_name,
+    b.display_name,
+    b.category,
+    b.solo,
+    b.value_type,
+    r.date,
+    r.value,
+    rr.position,
+    r.status,
+    r.submitted_by,
+    r.reviewed_by,
+    r.review_reason,
+    tm.user_id,
+    tm.guild_id,
+    (SELECT json_agg(e ORDER BY e.evidence_id) FROM record_evidence e WHERE e.record_id = r.record_id) AS evidence
+FROM records r
+JOIN teams tm ON r.record_id = tm.record_id AND r.guild_id = tm.guild_id
+JOIN bosses b ON r.boss_name = b.name
+LEFT JOIN record_rankings rr ON rr.record_id = r.record_id AND rr.guild_id = @guild_id
+WHERE r.guild_id = @guild_id AND r.record_id = @record_id AND r.deleted_at IS NULL
+ORDER BY tm.user_id;
+
+-- name: CreateRecordEvidence :execrows
+INSERT INTO record_evidence (record_id, guild_id, kind, url, submitted_by)
+SELECT r.record_id, r.guild_id, e.kind, e.url, e.submitted_by
+FROM records r
+CROSS JOIN unnest(@kinds::text[], @urls::text[], @submitted_by::text[]) AS e(kind, url, submitted_by)
+WHERE r.guild_id = @guild_id AND r.record_id = @record_id AND r.deleted_at IS NULL;
+
+-- name: GetRecordStatus :one
+SELECT status
+FROM records
+WHERE guild_id = @guild_id AND record_id = @record_id AND deleted_at IS NULL
+FOR UPDATE;
+
+-- name: ReviewRecord :one
+UPDATE records r
+SET status = @status,
+    reviewed_by = @reviewed_by,
+    reviewed_at = now(),
+    review_reason = sqlc.narg(review_reason)
+FROM bosses b
+WHERE r.guild_id = @guild_id
+AND r.record_id = @record_id
+AND r.status = 'pending'
+AND r.deleted_at IS NULL
+AND b.name = r.boss_name
+RETURNING r.record_id, r.boss_name, b.value_type, r.value, r.season_id;
+
 -- ==================== Detailed Guild ====================
 
 -- name: GetDetailedGuild :one
-WITH ranked_records AS (
-    -- Team boss records ranked directly
-    SELECT r.record_id, r.value, r.boss_name, r.date, r.guild_id,
-           ROW_NUMBER() OVER (
-               PARTITION BY r.boss_name
-               ORDER BY CASE WHEN vt.higher_is_better THEN -r.value ELSE r.value END ASC
-           ) as position
-    FROM records r
-    JOIN bosses b ON r.boss_name = b.name
-    JOIN value_types vt ON b.value_type = vt.name
-    WHERE r.guild_id = @guild_id AND b.solo = false
-
-    UNION ALL
-
-    -- Solo boss records: best per user, then ranked
-    SELECT s.record_id, s.value, s.boss_name, s.date, s.guild_id,
-           ROW_NUMBER() OVER (
-               PARTITION BY s.boss_name
-               ORDER BY CASE WHEN s.higher_is_better THEN -s.value ELSE s.value END ASC
-           ) as position
-    FROM (
-        SELECT DISTINCT ON (tm.user_id, r.boss_name)
-            r.record_id, r.value, r.boss_name, r.date, r.guild_id, vt.higher_is_better
-        FROM records r
-        JOIN teams tm ON r.record_id = tm.record_id AND r.guild_id = tm.guild_id
-        JOIN bosses b ON r.boss_name = b.name
-        JOIN value_types vt ON b.value_type = vt.name
-        WHERE r.guild_id = @guild_id AND b.solo = true
-        ORDER BY tm.user_id, r.boss_name,
-                 CASE WHEN vt.higher_is_better THEN -r.value ELSE r.value END ASC
-    ) s
-),
-top_records AS (
-    SELECT record_id, value, boss_name, date, guild_id, position
-    FROM ranked_records
-    WHERE position <= (SELECT position_count FROM guilds WHERE guild_id = @guild_id)
+WITH top_records AS (
+    SELECT rr.record_id, rr.value, rr.boss_name, b.value_type, rr.date, rr.guild_id, rr.position
+    FROM record_rankings rr
+    JOIN bosses b ON rr.boss_name = b.name
+    JOIN guild_bosses gb ON gb.boss = rr.boss_name AND gb.guild_id = rr.guild_id
+    JOIN guild_categories gc ON gc.guild_id = gb.guild_id AND gc.category = gb.category
+    WHERE rr.guild_id = @guild_id AND rr.season_id = @season_id
+      AND gb.enabled AND gc.enabled
+      AND rr.position <= (SELECT position_count FROM guilds WHERE guild_id = @guild_id)
 )
 SELECT
     g.guild_id,
@@ -299,8 +597,12 @@ SELECT
     g.pb_channel_id,
     g.mod_channel_id,
     g.position_count,
+    g.tie_policy,
+    g.seasonal_points,
+    @season_id::int AS season_id,
     (SELECT count(user_id) FROM users WHERE users.guild_id = @guild_id) as user_count,
-    (SELECT count(record_id) FROM records WHERE records.guild_id = @guild_id) as record_count,
+    (SELECT count(record_id) FROM records WHERE records.guild_id = @guild_id AND records.status = 'approved' AND records.deleted_at IS NULL
+     AND records.season_id = @season_id) as record_count,
 
     (SELECT json_agg(tm) FROM teams tm
      WHERE tm.guild_id = g.guild_id
@@ -308,19 +610,25 @@ SELECT
 
     (SELECT json_agg(tr) FROM top_records tr) AS records,
 
-    (SELECT json_agg(b) FROM bosses b
+    (SELECT json_agg(e ORDER BY e.evidence_id) FROM record_evidence e
+     WHERE e.guild_id = g.guild_id
+     AND e.record_id IN (SELECT tr.record_id FROM top_records tr)) AS evidence,
+
+    (SELECT json_agg(b ORDER BY gb.display_order NULLS LAST, b.display_name) FROM bosses b
      JOIN guild_bosses gb ON b.name = gb.boss
-     WHERE gb.guild_id = g.guild_id) AS bosses,
+     JOIN guild_categories gc ON gc.guild_id = gb.guild_id AND gc.category = gb.category
+     WHERE gb.guild_id = g.guild_id AND gb.enabled AND gc.enabled) AS bosses,
 
-    (SELECT json_agg(c) FROM categories c
+    (SELECT json_agg(c ORDER BY COALESCE(gc.display_order, c."order"), c.name) FROM categories c
      JOIN guild_categories gc ON c.name = gc.category
-     WHERE gc.guild_id = g.guild_id) AS categories,
+     WHERE gc.guild_id = g.guild_id AND gc.enabled) AS categories,
 
-    (SELECT json_agg(gb) FROM guild_bosses gb
-     WHERE gb.guild_id = g.guild_id) AS guild_bosses,
+    (SELECT json_agg(gb ORDER BY gb.display_order NULLS LAST, gb.boss) FROM guild_bosses gb
+     JOIN guild_categories gc ON gc.guild_id = gb.guild_id AND gc.category = gb.category
+     WHERE gb.guild_id = g.guild_id AND gb.enabled AND gc.enabled) AS guild_bosses,
 
-    (SELECT json_agg(gc) FROM guild_categories gc
-     WHERE gc.guild_id = g.guild_id) AS guild_categories
+    (SELECT json_agg(gc ORDER BY gc.display_order NULLS LAST, gc.category) FROM guild_categories gc
+     WHERE gc.guild_id = g.guild_id AND gc.enabled) AS guild_categories
 
 FROM guilds g
 WHERE g.guild_id = @guild_id;
@@ -337,32 +645,50 @@ SELECT
     b.value_type,
     r.date,
     r.value,
+    rr.position,
     tm.user_id,
-    tm.guild_id
+    tm.guild_id,
+    (SELECT json_agg(e ORDER BY e.evidence_id) FROM record_evidence e WHERE e.record_id = r.record_id) AS evidence
 FROM records r
 JOIN teams tm ON r.record_id = tm.record_id AND r.guild_id = tm.guild_id
 JOIN bosses b ON r.boss_name = b.name
-WHERE tm.user_id = @user_id AND tm.guild_id = @guild_id
+LEFT JOIN record_rankings rr ON rr.record_id = r.record_id AND rr.guild_id = @guild_id
+WHERE tm.user_id = @user_id AND tm.guild_id = @guild_id AND r.status = 'approved' AND r.deleted_at IS NULL
+AND r.season_id = @season_id
 ORDER BY r.record_id;
 
--- ==================== User Rank & Tier ====================
+-- name: GetUsersRecords :many
+SELECT
+    r.record_id,
+    r.boss_name,
+    b.display_name,
+    b.category,
+    b.solo,
+    b.value_type,
+    r.date,
+    r.value,
+    rr.position,
+    tm.user_id,
+    tm.guild_id,
+    (SELECT json_agg(e ORDER BY e.evidence_id) FROM record_evidence e WHERE e.record_id = r.record_id) AS evidence
+FROM records r
+JOIN teams tm ON r.record_id = tm.record_id AND r.guild_id = tm.guild_id
+JOIN bosses b ON r.boss_name = b.name
+LEFT JOIN record_rankings rr ON rr.record_id = r.record_id AND rr.guild_id = @guild_id
+WHERE tm.user_id = ANY(@user_ids::text[]) AND tm.guild_id = @guild_id AND r.status = 'approved' AND r.deleted_at IS NULL
+AND r.season_id = @season_id
+ORDER BY tm.user_id, r.record_id;
+
+-- ==================== User Rank ====================
 
--- name: GetUserRank :one
+-- name: GetUsersRank :many
 WITH ranked_users AS (
     SELECT user_id, RANK() OVER (ORDER BY points DESC) as user_rank
     FROM users
     WHERE guild_id = @guild_id
 )
-SELECT user_rank FROM ranked_users
-WHERE user_id = @user_id;
-
--- name: GetUserTier :one
-SELECT gr.name, gr.icon, gr.role_id, gr.min_points, gr.display_order
-FROM guild_ranks gr
-WHERE gr.guild_id = @guild_id
-AND gr.min_points <= @points
-ORDER BY gr.min_points DESC
-LIMIT 1;
+SELECT user_id, user_rank FROM ranked_users
+WHERE user_id = ANY(@user_ids::text[]);
 
 -- ==================== Guild Ranks ====================
 
@@ -372,6 +698,12 @@ FROM guild_ranks
 WHERE guild_id = @guild_id
 ORDER BY display_order;
 
+-- name: GetGuildUserPoints :many
+SELECT user_id, points
+FROM users
+WHERE guild_id = @guild_id
+ORDER BY points DESC, user_id;
+
 -- name: CreateGuildRank :exec
 INSERT INTO guild_ranks (guild_id, name, min_points, icon, role_id, display_order)
 VALUES (@guild_id, @name, @min_points, @icon, @role_id, @display_order);
@@ -390,7 +722,7 @@ DELETE FROM guild_ranks WHERE guild_id = @guild_id AND name = @name;
 -- ==================== Value Types ====================
 
 -- name: GetValueTypes :many
-SELECT name, higher_is_better FROM value_types ORDER BY name;
+SELECT name, higher_is_better, unit, display_format, min_value, max_value, step FROM value_types ORDER BY name;
 
 -- ==================== Misc ====================
 
@@ -403,10 +735,12 @@ WHERE guild_categories.guild_id = @guild_id
 AND guild_categories.category = u.category;
 
 -- name: GetBosses :many
-SELECT name, display_name, category, solo, value_type FROM bosses;
+SELECT name, display_name, category, solo, value_type, released_at, guild_id, retired FROM bosses
+WHERE guild_id IS NULL;
 
 -- name: GetCategories :many
-SELECT "thumbnail", "order", "name" FROM categories;
+SELECT "thumbnail", "order", "name", "guild_id", "retired" FROM categories
+WHERE guild_id IS NULL;
 
 -- name: GetAchievements :many
 SELECT "name", "thumbnail", "discord_icon", "order" FROM achievement;
@@ -423,19 +757,40 @@ SET points = @points
 WHERE ps.guild_id = @guild_id
 AND ps.source = @point_source;
 
+-- name: CreateGuildPointSource :one
+INSERT INTO point_sources (guild_id, source, points, name)
+VALUES (@guild_id, @source, @points, @name)
+RETURNING "source", "points", "name";
+
+-- name: EditGuildPointSource :one
+UPDATE point_sources
+SET
+    name = COALESCE(sqlc.narg(name), name),
+    points = COALESCE(sqlc.narg(points), points)
+WHERE guild_id = @guild_id
+AND source = @point_source
+RETURNING "source", "points", "name";
+
+-- name: DeleteGuildPointSource :execrows
+DELETE FROM point_sources
+WHERE guild_id = @guild_id
+AND source = @point_source;
+
 -- name: CreateEvent :exec
 INSERT INTO event (
 	name,
 	wom_id,
 	guild_id,
 	position_cutoff,
-	solo
+	solo,
+	season_id
 ) VALUES (
 	@name,
 	@wom_id,
 	@guild_id,
 	@position_cutoff,
-	@solo
+	@solo,
+	(SELECT season_id FROM guild_seasons WHERE guild_id = @guild_id AND ended_at IS NULL)
 );
 
 -- name: InsertEventParticipants :exec
@@ -511,12 +866,24 @@ JOIN achievement a ON ua.achievement_name = a.name
 WHERE ua.user_id = @user_id
 ORDER BY a.order;
 
--- name: GetUserRsns :many
+-- name: GetUsersAchievements :many
 SELECT
+	ua.user_id,
+	a.name,
+	a.thumbnail,
+	a.discord_icon
+FROM user_achievement ua
+JOIN achievement a ON ua.achievement_name = a.name
+WHERE ua.user_id = ANY(@user_ids::text[])
+ORDER BY ua.user_id, a.order;
+
+-- name: GetUsersRsns :many
+SELECT
+	r.user_id,
 	r.rsn,
 	r.wom_id
 FROM rsn r
-WHERE r.user_id = @user_id AND r.guild_id = @guild_id;
+WHERE r.user_id = ANY(@user_ids::text[]) AND r.guild_id = @guild_id;
 
 -- name: GetUserByWom :many
 SELECT
@@ -556,6 +923,22 @@ SELECT
 FROM event e
 JOIN event_participant ep ON e.wom_id = ep.event_id
 WHERE ep.user_id = @user_id AND ep.guild_id = @guild_id
+AND e.season_id = @season_id
+AND ep.placement <= e.position_cutoff;
+
+-- name: GetUsersEvents :many
+SELECT
+    e.name,
+    e.wom_id AS event_id,
+    e.guild_id,
+    ep.user_id,
+    ep.placement,
+    e.position_cutoff,
+    e.solo
+FROM event e
+JOIN event_participant ep ON e.wom_id = ep.event_id
+WHERE ep.user_id = ANY(@user_ids::text[]) AND ep.guild_id = @guild_id
+AND e.season_id = @season_id
 AND ep.placement <= e.position_cutoff;
 
 -- name: GiveAchievementById :exec
@@ -617,10 +1000,10 @@ INSERT INTO user_combat_achievement (user_id, guild_id, combat_achievement_name)
 SELECT unnest(@user_ids::text[]), @guild_id, @combat_achievement_name
 ON CONFLICT ON CONSTRAINT "user_combat_achievement_pkey" DO NOTHING;
 
--- name: GetUserCombatAchievements :many
-SELECT uca.combat_achievement_name
+-- name: GetUsersCombatAchievements :many
+SELECT uca.user_id, uca.combat_achievement_name
 FROM user_combat_achievement uca
-WHERE uca.user_id = @user_id AND uca.guild_id = @guild_id;
+WHERE uca.user_id = ANY(@user_ids::text[]) AND uca.guild_id = @guild_id;
 
 -- name: GiveUserCombatAchievement :exec
 INSERT INTO user_combat_achievement (user_id, guild_id, combat_achievement_name)
@@ -636,3 +1019,314 @@ AND combat_achievement_name = @combat_achievement_name;
 -- name: DeleteRsn :execrows
 DELETE FROM rsn r
 WHERE r.guild_id = @guild_id AND r.user_id = @user_id AND r.rsn = @rsn;
+
+-- ==================== API Keys ====================
+
+-- name: GetAPIKeyByHash :one
+SELECT key_id, guild_id, name, role
+FROM api_keys
+WHERE key_hash = @key_hash
+AND revoked_at IS NULL;
+
+-- name: GetGuildAPIKeys :many
+SELECT key_id, guild_id, name, role, key_prefix, created_at, rotated_at
+FROM api_keys
+WHERE guild_id = @guild_id
+AND revoked_at IS NULL
+ORDER BY key_id;
+
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (guild_id, name, role, key_prefix, key_hash)
+VALUES (@guild_id, @name, @role, @key_prefix, @key_hash)
+RETURNING key_id, guild_id, name, role, key_prefix, created_at, rotated_at;
+
+-- name: RotateAPIKey :one
+UPDATE api_keys SET
+    key_prefix = @key_prefix,
+    key_hash = @key_hash,
+    rotated_at = now()
+WHERE guild_id = @guild_id
+AND key_id = @key_id
+AND revoked_at IS NULL
+RETURNING key_id, guild_id, name, role, key_prefix, created_at, rotated_at;
+
+-- name: RevokeAPIKey :execrows
+UPDATE api_keys SET revoked_at = now()
+WHERE guild_id = @guild_id
+AND key_id = @key_id
+AND revoked_at IS NULL;
+
+-- ==================== Multiplier Windows ====================
+
+-- name: GetGuildMultiplierWindows :many
+SELECT window_id, name, multiplier, starts_at, ends_at
+FROM guild_multiplier_windows
+WHERE guild_id = @guild_id
+AND ends_at > now()
+ORDER BY starts_at;
+
+-- name: CreateGuildMultiplierWindow :one
+INSERT INTO guild_multiplier_windows (guild_id, name, multiplier, starts_at, ends_at)
+VALUES (@guild_id, @name, @multiplier, @starts_at, @ends_at)
+RETURNING window_id, name, multiplier, starts_at, ends_at;
+
+-- name: DeleteGuildMultiplierWindow :execrows
+DELETE FROM guild_multiplier_windows
+WHERE guild_id = @guild_id
+AND window_id = @window_id;
+
+-- ==================== Seasons ====================
+
+-- name: GetSeason :one
+SELECT s.season_id, s.name, s.started_at, s.ended_at, g.seasonal_points
+FROM guild_seasons s
+JOIN guilds g ON s.guild_id = g.guild_id
+WHERE s.guild_id = @guild_id
+AND (s.season_id = sqlc.narg(season_id) OR (sqlc.narg(season_id) IS NULL AND s.ended_at IS NULL));
+
+-- name: GetGuildSeasons :many
+SELECT season_id, name, started_at, ended_at,
+    (SELECT count(record_id) FROM records r WHERE r.season_id = s.season_id AND r.status = 'approved' AND r.deleted_at IS NULL) AS record_count
+FROM guild_seasons s
+WHERE guild_id = @guild_id
+ORDER BY started_at DESC, season_id DESC;
+
+-- name: LockGuildSeasons :one
+-- Serializes season changes per guild without blocking inserts that only
+-- reference the guild
+SELECT guild_id
+FROM guilds
+WHERE guild_id = @guild_id
+FOR NO KEY UPDATE;
+
+-- name: CloseSeason :one
+UPDATE guild_seasons
+SET ended_at = now()
+WHERE guild_id = @guild_id AND ended_at IS NULL
+RETURNING season_id, name, started_at, ended_at;
+
+-- name: CreateSeason :one
+INSERT INTO guild_seasons (guild_id, name)
+VALUES (
+    @guild_id,
+    COALESCE(sqlc.narg(name), 'Season ' || ((SELECT count(*) FROM guild_seasons WHERE guild_id = @guild_id) + 1))
+)
+RETURNING season_id, name, started_at, ended_at;
+
+-- ==================== Custom Bosses ====================
+
+-- name: GetGuildAvailableBosses :many
+SELECT b.name, b.display_name, b.category, b.solo, b.value_type, b.released_at, b.guild_id, b.retired FROM bosses b
+JOIN guild_bosses gb ON gb.boss = b.name
+JOIN guild_categories gc ON gc.guild_id = gb.guild_id AND gc.category = gb.category
+WHERE gb.guild_id = @guild_id AND gb.enabled AND gc.enabled AND NOT b.retired;
+
+-- name: GetGuildCustomBosses :many
+SELECT name, display_name, category, solo, value_type, released_at, guild_id, retired FROM bosses
+WHERE guild_id = @guild_id::text
+ORDER BY category, name;
+
+-- name: IsCategoryAvailable :one
+SELECT EXISTS (
+    SELECT 1 FROM categories
+    WHERE name = @category AND ((guild_id IS NULL AND NOT retired) OR guild_id = @guild_id::text)
+);
+
+-- name: CreateCustomBoss :one
+INSERT INTO bosses (name, display_name, category, solo, value_type, guild_id)
+VALUES (@name, @display_name, @category, @solo, @value_type, @guild_id::text)
+RETURNING name, display_name, category, solo, value_type, released_at, guild_id, retired;
+
+-- name: CreateGuildBoss :exec
+INSERT INTO guild_bosses (boss, guild_id, category)
+VALUES (@boss, @guild_id, @category);
+
+-- name: UpdateCustomBoss :one
+UPDATE bosses
+SET
+    display_name = COALESCE(sqlc.narg(display_name), display_name),
+    category = COALESCE(sqlc.narg(category), category)
+WHERE name = @boss_name AND guild_id = @guild_id::text
+RETURNING name, display_name, category, solo, value_type, released_at, guild_id, retired;
+
+-- name: UpdateGuildBossCategory :exec
+UPDATE guild_bosses
+SET category = @category
+WHERE boss = @boss AND guild_id = @guild_id;
+
+-- name: GetCustomBossRecordCount :one
+-- Deleted records count too, they can still be restored and deleting the
+-- boss would cascade to them
+SELECT count(r.record_id)
+FROM bosses b
+LEFT JOIN records r ON r.boss_name = b.name
+WHERE b.name = @boss_name AND b.guild_id = @guild_id::text
+GROUP BY b.name;
+
+-- name: DeleteCustomBoss :execrows
+DELETE FROM bosses
+WHERE name = @boss_name AND guild_id = @guild_id::text;
+
+-- name: GetGuildCustomCategories :many
+SELECT "thumbnail", "order", "name", "guild_id", "retired" FROM categories
+WHERE guild_id = @guild_id::text
+ORDER BY "order", "name";
+
+-- name: CreateCustomCategory :one
+INSERT INTO categories ("name", "thumbnail", "order", "guild_id")
+VALUES (@name, sqlc.narg(thumbnail), @order, @guild_id::text)
+RETURNING "thumbnail", "order", "name", "guild_id", "retired";
+
+-- name: CreateGuildCategory :exec
+INSERT INTO guild_categories (guild_id, category)
+VALUES (@guild_id, @category);
+
+-- name: UpdateCustomCategory :one
+UPDATE categories
+SET
+    thumbnail = COALESCE(sqlc.narg(thumbnail), thumbnail),
+    "order" = COALESCE(sqlc.narg(category_order), "order")
+WHERE name = @category AND guild_id = @guild_id::text
+RETURNING "thumbnail", "order", "name", "guild_id", "retired";
+
+-- name: GetCustomCategoryBossCount :one
+SELECT count(b.name)
+FROM categories c
+LEFT JOIN bosses b ON b.category = c.name
+WHERE c.name = @category AND c.guild_id = @guild_id::text
+GROUP BY c.name;
+
+-- name: DeleteCustomCategory :execrows
+DELETE FROM categories
+WHERE name = @category AND guild_id = @guild_id::text;
+
+-- ==================== Guild Bosses ====================
+
+-- name: GetGuildBosses :many
+SELECT
+    b.name,
+    b.display_name,
+    gb.category,
+    b.solo,
+    b.value_type,
+    b.guild_id IS NOT NULL AS custom,
+    b.retired,
+    gb.enabled,
+    gb.display_order
+FROM guild_bosses gb
+JOIN bosses b ON b.name = gb.boss
+WHERE gb.guild_id = @guild_id
+ORDER BY gb.category, gb.display_order NULLS LAST, b.display_name;
+
+-- name: UpdateGuildBoss :execrows
+UPDATE guild_bosses
+SET
+    enabled = COALESCE(sqlc.narg(enabled), enabled),
+    display_order = CASE WHEN @reset_order::bool THEN NULL
+                         ELSE COALESCE(sqlc.narg(display_order), display_order) END
+WHERE guild_id = @guild_id AND boss = @boss;
+
+-- name: GetGuildCategories :many
+SELECT
+    c.name,
+    c.thumbnail,
+    c."order",
+    c.guild_id IS NOT NULL AS custom,
+    gc.enabled,
+    gc.display_order
+FROM guild_categories gc
+JOIN categories c ON c.name = gc.category
+WHERE gc.guild_id = @guild_id
+ORDER BY COALESCE(gc.display_order, c."order"), c.name;
+
+-- name: UpdateGuildCategory :execrows
+UPDATE guild_categories
+SET
+    enabled = COALESCE(sqlc.narg(enabled), enabled),
+    display_order = CASE WHEN @reset_order::bool THEN NULL
+                         ELSE COALESCE(sqlc.narg(display_order), display_order) END
+WHERE guild_id = @guild_id AND category = @category;
+
+-- name: SyncGuildCategories :execrows
+INSERT INTO guild_categories (guild_id, category)
+SELECT g.guild_id, c.name
+FROM guilds g
+CROSS JOIN categories c
+WHERE c.guild_id IS NULL AND NOT c.retired
+AND (sqlc.narg(guild_id)::text IS NULL OR g.guild_id = sqlc.narg(guild_id)::text)
+ON CONFLICT DO NOTHING;
+
+-- name: SyncGuildBosses :execrows
+INSERT INTO guild_bosses (guild_id, boss, category)
+SELECT g.guild_id, b.name, b.category
+FROM guilds g
+CROSS JOIN bosses b
+WHERE b.guild_id IS NULL AND b.category IS NOT NULL AND NOT b.retired
+AND (sqlc.narg(guild_id)::text IS NULL OR g.guild_id = sqlc.narg(guild_id)::text)
+ON CONFLICT DO NOTHING;
+
+-- ==================== Boss Catalog ====================
+
+-- name: IsCatalogCategory :one
+SELECT EXISTS (
+    SELECT 1 FROM categories
+    WHERE name = @category AND guild_id IS NULL AND NOT retired
+);
+
+-- name: CreateBoss :one
+INSERT INTO bosses (name, display_name, category, solo, value_type, released_at)
+VALUES (@name, @display_name, @category, @solo, @value_type, sqlc.narg(released_at))
+RETURNING name, display_name, category, solo, value_type, released_at, guild_id, retired;
+
+-- name: UpdateBoss :one
+UPDATE bosses
+SET
+    display_name = COALESCE(sqlc.narg(display_name), display_name),
+    category = COALESCE(sqlc.narg(category), category),
+    released_at = COALESCE(sqlc.narg(released_at), released_at),
+    retired = COALESCE(sqlc.narg(retired), retired)
+WHERE name = @boss_name AND guild_id IS NULL
+RETURNING name, display_name, category, solo, value_type, released_at, guild_id, retired;
+
+-- name: UpdateAllGuildBossesCategory :exec
+UPDATE guild_bosses
+SET category = @category
+WHERE boss = @boss;
+
+-- name: CreateCategory :one
+INSERT INTO categories ("name", "thumbnail", "order")
+VALUES (@name, sqlc.narg(thumbnail), @order)
+RETURNING "thumbnail", "order", "name", "guild_id", "retired";
+
+-- name: GetCategoryActiveBossCount :one
+-- Guild custom bosses don't hold a category back, they stay in it after it's
+-- retired
+SELECT count(b.name)
+FROM categories c
+LEFT JOIN bosses b ON b.category = c.name AND b.guild_id IS NULL AND NOT b.retired
+WHERE c.name = @category AND c.guild_id IS NULL
+GROUP BY c.name;
+
+-- name: UpdateCategory :one
+UPDATE categories
+SET
+    thumbnail = COALESCE(sqlc.narg(thumbnail), thumbnail),
+    "order" = COALESCE(sqlc.narg(category_order), "order"),
+    retired = COALESCE(sqlc.narg(retired), retired)
+WHERE name = @category AND guild_id IS NULL
+RETURNING "thumbnail", "order", "name", "guild_id", "retired";
+
+-- name: CreateValueType :one
+INSERT INTO value_types (name, higher_is_better, unit, display_format, min_value, max_value, step)
+VALUES (@name, @higher_is_better, sqlc.narg(unit), @display_format, sqlc.narg(min_value), sqlc.narg(max_value), @step)
+RETURNING name, higher_is_better, unit, display_format, min_value, max_value, step;
+
+-- name: UpdateValueType :one
+UPDATE value_types
+SET
+    higher_is_better = COALESCE(sqlc.narg(higher_is_better), higher_is_better),
+    unit = COALESCE(sqlc.narg(unit), unit),
+    min_value = CASE WHEN @clear_bounds::bool THEN NULL ELSE COALESCE(sqlc.narg(min_value), min_value) END,
+    max_value = CASE WHEN @clear_bounds::bool THEN NULL ELSE COALESCE(sqlc.narg(max_value), max_value) END
+WHERE name = @value_type
+RETURNING name, higher_is_better, unit, display_format, min_value, max_value, step;
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+
+	"tectonic-api/database"
+	"tectonic-api/logging"
+	"tectonic-api/models"
+	"tectonic-api/utils"
+)
+
+type GetAPIKeysInput struct {
+	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+}
+type GetAPIKeysOutput struct {
+	Body []models.APIKeyResponse
+}
+
+func (s *Server) GetAPIKeys(ctx context.Context, input *GetAPIKeysInput) (*GetAPIKeysOutput, error) {
+	rows, ei := database.WrapQuery(s.queries.GetGuildAPIKeys, ctx, input.GuildID)
+	if ei != nil {
+		return nil, s.dbError(*ei)
+	}
+	return &GetAPIKeysOutput{Body: models.APIKeysFromRows(rows)}, nil
+}
+
+type CreateAPIKeyInput struct {
+	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+	Body    models.CreateAPIKeyBody
+}
+type CreateAPIKeyOutput struct {
+	Body models.APIKeyResponse
+}
+
+func (s *Server) CreateAPIKey(ctx context.Context, input *CreateAPIKeyInput) (*CreateAPIKeyOutput, error) {
+	key, prefix, hash, err := utils.GenerateAPIKey()
+	if err != nil {
+		logging.Get().Error("Error generating API key", "error", err)
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+
+	row, err := s.queries.CreateAPIKey(ctx, database.CreateAPIKeyParams{
+		GuildID:   input.GuildID,
+		Name:      input.Body.Name,
+		Role:      string(input.Body.Role),
+		KeyPrefix: prefix,
+		KeyHash:   hash,
+	})
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+
+	res := models.APIKeyFromRow(database.GetGuildAPIKeysRow(row))
+	res.Key = key
+	return &CreateAPIKeyOutput{Body: res}, nil
+}
+
+type RotateAPIKeyInput struct {
+	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+	KeyID   int    `path:"key_id" doc:"API key ID"`
+}
+type RotateAPIKeyOutput struct {
+	Body models.APIKeyResponse
+}
+
+func (s *Server) RotateAPIKey(ctx context.Context, input *RotateAPIKeyInput) (*RotateAPIKeyOutput, error) {
+	key, prefix, hash, err := utils.GenerateAPIKey()
+	if err != nil {
+		logging.Get().Error("Error generating API key", "error", err)
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+
+	row, err := s.queries.RotateAPIKey(ctx, database.RotateAPIKeyParams{
+		KeyPrefix: prefix,
+		KeyHash:   hash,
+		GuildID:   input.GuildID,
+		KeyID:     int32(input.KeyID),
+	})
+	if ei := database.ClassifyError(err); ei != nil {
+		if ei.Recoverable && ei.Code == "P0002" {
+			return nil, models.NewTectonicError(models.ERROR_API_KEY_NOT_FOUND)
+		}
+		return nil, s.dbError(*ei)
+	}
+
+	res := models.APIKeyFromRow(database.GetGuildAPIKeysRow(row))
+	res.Key = key
+	return &RotateAPIKeyOutput{Body: res}, nil
+}
+
+type RevokeAPIKeyInput struct {
+	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+	KeyID   int    `path:"key_id" doc:"API key ID"`
+}
+
+func (s *Server) RevokeAPIKey(ctx context.Context, input *RevokeAPIKeyInput) (*struct{}, error) {
+	rows, ei := database.WrapQuery(s.queries.RevokeAPIKey, ctx, database.RevokeAPIKeyParams{
+		GuildID: input.GuildID,
+		KeyID:   int32(input.KeyID),
+	})
+	if ei != nil {
+		return nil, s.dbError(*ei)
+	}
+	if rows == 0 {
+		return nil, models.NewTectonicError(models.ERROR_API_KEY_NOT_FOUND)
+	}
+	return nil, nil
+}
@@ -0,0 +1,303 @@
+package handlers
+
+import (
+	"context"
+
+	"tectonic-api/database"
+	"tectonic-api/logging"
+	"tectonic-api/models"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type CreateBossInput struct {
+	Body models.CreateBossBody
+}
+type CatalogBossOutput struct {
+	Body database.Boss
+}
+
+// CreateBoss adds a global boss and hands it out to every guild right away
+func (s *Server) CreateBoss(ctx context.Context, input *CreateBossInput) (*CatalogBossOutput, error) {
+	if err := s.checkCatalogCategory(ctx, input.Body.Category); err != nil {
+		return nil, err
+	}
+
+	tx, err := database.CreateTx(ctx)
+	if err != nil {
+		logging.Get().Error("Error creating transaction", "error", err)
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	defer tx.Rollback(ctx)
+
+	q := s.queries.WithTx(tx)
+
+	params := database.CreateBossParams{
+		Name:        input.Body.Name,
+		DisplayName: input.Body.DisplayName,
+		Category:    input.Body.Category,
+		Solo:        input.Body.Solo,
+		ValueType:   input.Body.ValueType,
+	}
+	if input.Body.ReleasedAt != nil {
+		params.ReleasedAt = pgtype.Date{Time: *input.Body.ReleasedAt, Valid: true}
+	}
+	boss, err := q.CreateBoss(ctx, params)
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+
+	if _, err := s.syncGuildCatalog(ctx, q, pgtype.Text{}); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	return &CatalogBossOutput{Body: boss}, nil
+}
+
+type UpdateBossInput struct {
+	Boss string `path:"boss" doc:"Boss name"`
+	Body models.UpdateBossBody
+}
+
+// UpdateBoss renames, re-categorizes, retires or brings back a global boss.
+// Guilds follow a new category and get the boss back once it's unretired.
+func (s *Server) UpdateBoss(ctx context.Context, input *UpdateBossInput) (*CatalogBossOutput, error) {
+	params := database.UpdateBossParams{BossName: input.Boss}
+	if input.Body.DisplayName != nil {
+		params.DisplayName = pgtype.Text{String: *input.Body.DisplayName, Valid: true}
+	}
+	if input.Body.Category != nil {
+		if err := s.checkCatalogCategory(ctx, *input.Body.Category); err != nil {
+			return nil, err
+		}
+		params.Category = pgtype.Text{String: *input.Body.Category, Valid: true}
+	}
+	if input.Body.ReleasedAt != nil {
+		params.ReleasedAt = pgtype.Date{Time: *input.Body.ReleasedAt, Valid: true}
+	}
+	if input.Body.Retired != nil {
+		params.Retired = pgtype.Bool{Bool: *input.Body.Retired, Valid: true}
+	}
+
+	tx, err := database.CreateTx(ctx)
+	if err != nil {
+		logging.Get().Error("Error creating transaction", "error", err)
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	defer tx.Rollback(ctx)
+
+	q := s.queries.WithTx(tx)
+
+	boss, err := q.UpdateBoss(ctx, params)
+	if ei := database.ClassifyError(err); ei != nil {
+		if ei.Recoverable && ei.Code == "P0002" {
+			return nil, models.NewTectonicError(models.ERROR_BOSS_NOT_FOUND)
+		}
+		return nil, s.dbError(*ei)
+	}
+
+	if input.Body.Category != nil {
+		err = q.UpdateAllGuildBossesCategory(ctx, database.UpdateAllGuildBossesCategoryParams{
+			Category: boss.Category,
+			Boss:     boss.Name,
+		})
+		if ei := database.ClassifyError(err); ei != nil {
+			return nil, s.dbError(*ei)
+		}
+	}
+
+	if _, err := s.syncGuildCatalog(ctx, q, pgtype.Text{}); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	return &CatalogBossOutput{Body: boss}, nil
+}
+
+// checkCatalogCategory makes sure global bosses only use active global
+// categories
+func (s *Server) checkCatalogCategory(ctx context.Context, category string) error {
+	ok, err := s.queries.IsCatalogCategory(ctx, category)
+	if ei := database.ClassifyError(err); ei != nil {
+		return s.dbError(*ei)
+	}
+	if !ok {
+		return models.NewTectonicError(models.ERROR_CATEGORY_NOT_FOUND)
+	}
+	return nil
+}
+
+type CreateCategoryInput struct {
+	Body models.CreateCategoryBody
+}
+type CatalogCategoryOutput struct {
+	Body database.Category
+}
+
+func (s *Server) CreateCategory(ctx context.Context, input *CreateCategoryInput) (*CatalogCategoryOutput, error) {
+	tx, err := database.CreateTx(ctx)
+	if err != nil {
+		logging.Get().Error("Error creating transaction", "error", err)
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	defer tx.Rollback(ctx)
+
+	q := s.queries.WithTx(tx)
+
+	params := database.CreateCategoryParams{
+		Name:  input.Body.Name,
+		Order: int16(input.Body.Order),
+	}
+	if input.Body.Thumbnail != nil {
+		params.Thumbnail = pgtype.Text{String: *input.Body.Thumbnail, Valid: true}
+	}
+	category, err := q.CreateCategory(ctx, params)
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+
+	if _, err := s.syncGuildCatalog(ctx, q, pgtype.Text{}); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	return &CatalogCategoryOutput{Body: category}, nil
+}
+
+type UpdateCategoryInput struct {
+	Category string `path:"category" doc:"Category name"`
+	Body     models.UpdateCategoryBody
+}
+
+// UpdateCategory changes a global category, it can only be retired once all
+// of its global bosses are retired or moved. Custom bosses keep the retired
+// category but no new ones can be added to it.
+func (s *Server) UpdateCategory(ctx context.Context, input *UpdateCategoryInput) (*CatalogCategoryOutput, error) {
+	params := database.UpdateCategoryParams{Category: input.Category}
+	if input.Body.Thumbnail != nil {
+		params.Thumbnail = pgtype.Text{String: *input.Body.Thumbnail, Valid: true}
+	}
+	if input.Body.Order != nil {
+		params.CategoryOrder = pgtype.Int2{Int16: int16(*input.Body.Order), Valid: true}
+	}
+	if input.Body.Retired != nil {
+		params.Retired = pgtype.Bool{Bool: *input.Body.Retired, Valid: true}
+	}
+
+	tx, err := database.CreateTx(ctx)
+	if err != nil {
+		logging.Get().Error("Error creating transaction", "error", err)
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	defer tx.Rollback(ctx)
+
+	q := s.queries.WithTx(tx)
+
+	if input.Body.Retired != nil && *input.Body.Retired {
+		count, err := q.GetCategoryActiveBossCount(ctx, input.Category)
+		if ei := database.ClassifyError(err); ei != nil {
+			if ei.Recoverable && ei.Code == "P0002" {
+				return nil, models.NewTectonicError(models.ERROR_CATEGORY_NOT_FOUND)
+			}
+			return nil, s.dbError(*ei)
+		}
+		if count > 0 {
+			return nil, models.NewTectonicError(models.ERROR_CATEGORY_IN_USE)
+		}
+	}
+
+	category, err := q.UpdateCategory(ctx, params)
+	if ei := database.ClassifyError(err); ei != nil {
+		if ei.Recoverable && ei.Code == "P0002" {
+			return nil, models.NewTectonicError(models.ERROR_CATEGORY_NOT_FOUND)
+		}
+		return nil, s.dbError(*ei)
+	}
+
+	if _, err := s.syncGuildCatalog(ctx, q, pgtype.Text{}); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	return &CatalogCategoryOutput{Body: category}, nil
+}
+
+type CreateValueTypeInput struct {
+	Body models.CreateValueTypeBody
+}
+type ValueTypeOutput struct {
+	Body database.ValueType
+}
+
+func (s *Server) CreateValueType(ctx context.Context, input *CreateValueTypeInput) (*ValueTypeOutput, error) {
+	params := database.CreateValueTypeParams{
+		Name:           input.Body.Name,
+		HigherIsBetter: input.Body.HigherIsBetter,
+		DisplayFormat:  input.Body.DisplayFormat,
+		Step:           int32(input.Body.Step),
+	}
+	if input.Body.Unit != nil {
+		params.Unit = pgtype.Text{String: *input.Body.Unit, Valid: true}
+	}
+	if input.Body.MinValue != nil {
+		params.MinValue = pgtype.Int4{Int32: int32(*input.Body.MinValue), Valid: true}
+	}
+	if input.Body.MaxValue != nil {
+		params.MaxValue = pgtype.Int4{Int32: int32(*input.Body.MaxValue), Valid: true}
+	}
+
+	valueType, err := s.queries.CreateValueType(ctx, params)
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+	return &ValueTypeOutput{Body: valueType}, nil
+}
+
+type UpdateValueTypeInput struct {
+	ValueType string `path:"value_type" doc:"Value type name"`
+	Body      models.UpdateValueTypeBody
+}
+
+// UpdateValueType can flip which direction ranks better, rankings follow on
+// the next read since they're computed by the record_rankings view. New
+// bounds only apply to records submitted afterwards.
+func (s *Server) UpdateValueType(ctx context.Context, input *UpdateValueTypeInput) (*ValueTypeOutput, error) {
+	params := database.UpdateValueTypeParams{
+		ClearBounds: input.Body.ClearBounds,
+		ValueType:   input.ValueType,
+	}
+	if input.Body.HigherIsBetter != nil {
+		params.HigherIsBetter = pgtype.Bool{Bool: *input.Body.HigherIsBetter, Valid: true}
+	}
+	if input.Body.Unit != nil {
+		params.Unit = pgtype.Text{String: *input.Body.Unit, Valid: true}
+	}
+	if input.Body.MinValue != nil {
+		params.MinValue = pgtype.Int4{Int32: int32(*input.Body.MinValue), Valid: true}
+	}
+	if input.Body.MaxValue != nil {
+		params.MaxValue = pgtype.Int4{Int32: int32(*input.Body.MaxValue), Valid: true}
+	}
+
+	valueType, err := s.queries.UpdateValueType(ctx, params)
+	if ei := database.ClassifyError(err); ei != nil {
+		if ei.Recoverable && ei.Code == "P0002" {
+			return nil, models.NewTectonicError(models.ERROR_VALUE_TYPE_NOT_FOUND)
+		}
+		// Only one bound was sent and it crosses the stored one
+		if ei.Recoverable && ei.Code == "23514" {
+			return nil, models.NewTectonicError(models.ERROR_VALIDATION_FAILED)
+		}
+		return nil, s.dbError(*ei)
+	}
+	return &ValueTypeOutput{Body: valueType}, nil
+}
@@ -5,6 +5,8 @@ import (
 
 	"tectonic-api/database"
 	"tectonic-api/models"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type GetGuildCombatAchievementsInput struct {
@@ -64,7 +66,7 @@ type CompleteCombatAchievementInput struct {
 	Body    models.CompleteCombatAchievementBody
 }
 type CompleteCombatAchievementOutput struct {
-	Body []database.UpdatePointsByEventRow
+	Body []models.UserPointsUpdate
 }
 
 func (s *Server) CompleteCombatAchievement(ctx context.Context, input *CompleteCombatAchievementInput) (*CompleteCombatAchievementOutput, error) {
@@ -85,9 +87,10 @@ func (s *Server) CompleteCombatAchievement(ctx context.Context, input *CompleteC
 	}
 
 	points, err := q.UpdatePointsByEvent(ctx, database.UpdatePointsByEventParams{
-		Event:   ca.PointSource,
-		GuildID: input.GuildID,
-		UserIds: models.SnowflakesToStrings(input.Body.UserIDs),
+		Event:             ca.PointSource,
+		GuildID:           input.GuildID,
+		UserIds:           models.SnowflakesToStrings(input.Body.UserIDs),
+		CombatAchievement: pgtype.Text{String: input.CAName, Valid: true},
 	})
 	if ei := database.ClassifyError(err); ei != nil {
 		return nil, s.dbError(*ei)
@@ -102,10 +105,15 @@ func (s *Server) CompleteCombatAchievement(ctx context.Context, input *CompleteC
 		return nil, s.dbError(*ei)
 	}
 
+	ranks, ei := database.WrapQuery(q.GetGuildRanks, ctx, input.GuildID)
+	if ei != nil {
+		return nil, s.dbError(*ei)
+	}
+
 	if err = tx.Commit(ctx); err != nil {
 		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
 	}
-	return &CompleteCombatAchievementOutput{Body: points}, nil
+	return &CompleteCombatAchievementOutput{Body: models.PointsUpdatesFromEventRows(points, ranks)}, nil
 }
 
 type UserCombatAchievementInput struct {
@@ -0,0 +1,325 @@
+package handlers
+
+import (
+	"context"
+
+	"tectonic-api/database"
+	"tectonic-api/logging"
+	"tectonic-api/models"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type GetCustomBossesInput struct {
+	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+}
+type GetCustomBossesOutput struct {
+	Body []database.Boss
+}
+
+func (s *Server) GetCustomBosses(ctx context.Context, input *GetCustomBossesInput) (*GetCustomBossesOutput, error) {
+	bosses, err := s.queries.GetGuildCustomBosses(ctx, input.GuildID)
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+	return &GetCustomBossesOutput{Body: bosses}, nil
+}
+
+type CreateCustomBossInput struct {
+	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+	Body    models.CreateCustomBossBody
+}
+type CustomBossOutput struct {
+	Body database.Boss
+}
+
+// CreateCustomBoss adds a boss owned by the guild under its guild prefixed
+// name, it's registered to the guild like a global boss so it ranks through
+// the same queries
+func (s *Server) CreateCustomBoss(ctx context.Context, input *CreateCustomBossInput) (*CustomBossOutput, error) {
+	if err := s.checkCategoryAvailable(ctx, input.GuildID, input.Body.Category); err != nil {
+		return nil, err
+	}
+
+	tx, err := database.CreateTx(ctx)
+	if err != nil {
+		logging.Get().Error("Error creating transaction", "error", err)
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	defer tx.Rollback(ctx)
+
+	q := s.queries.WithTx(tx)
+
+	boss, err := q.CreateCustomBoss(ctx, database.CreateCustomBossParams{
+		Name:        models.CustomName(input.GuildID, input.Body.Name),
+		DisplayName: input.Body.DisplayName,
+		Category:    input.Body.Category,
+		Solo:        input.Body.Solo,
+		ValueType:   input.Body.ValueType,
+		GuildID:     input.GuildID,
+	})
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+
+	err = q.CreateGuildBoss(ctx, database.CreateGuildBossParams{
+		Boss:     boss.Name,
+		GuildID:  input.GuildID,
+		Category: boss.Category,
+	})
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	return &CustomBossOutput{Body: boss}, nil
+}
+
+type UpdateCustomBossInput struct {
+	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+	Boss    string `path:"boss" doc:"Boss name with its guild prefix"`
+	Body    models.UpdateCustomBossBody
+}
+
+// UpdateCustomBoss renames or re-categorizes a custom boss. Solo and value
+// type are fixed once created since existing records depend on them.
+func (s *Server) UpdateCustomBoss(ctx context.Context, input *UpdateCustomBossInput) (*CustomBossOutput, error) {
+	params := database.UpdateCustomBossParams{
+		BossName: input.Boss,
+		GuildID:  input.GuildID,
+	}
+	if input.Body.DisplayName != nil {
+		params.DisplayName = pgtype.Text{String: *input.Body.DisplayName, Valid: true}
+	}
+	if input.Body.Category != nil {
+		if err := s.checkCategoryAvailable(ctx, input.GuildID, *input.Body.Category); err != nil {
+			return nil, err
+		}
+		params.Category = pgtype.Text{String: *input.Body.Category, Valid: true}
+	}
+
+	tx, err := database.CreateTx(ctx)
+	if err != nil {
+		logging.Get().Error("Error creating transaction", "error", err)
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	defer tx.Rollback(ctx)
+
+	q := s.queries.WithTx(tx)
+
+	boss, err := q.UpdateCustomBoss(ctx, params)
+	if ei := database.ClassifyError(err); ei != nil {
+		if ei.Recoverable && ei.Code == "P0002" {
+			return nil, models.NewTectonicError(models.ERROR_BOSS_NOT_FOUND)
+		}
+		return nil, s.dbError(*ei)
+	}
+
+	err = q.UpdateGuildBossCategory(ctx, database.UpdateGuildBossCategoryParams{
+		Category: boss.Category,
+		Boss:     boss.Name,
+		GuildID:  input.GuildID,
+	})
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	return &CustomBossOutput{Body: boss}, nil
+}
+
+type DeleteCustomBossInput struct {
+	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+	Boss    string `path:"boss" doc:"Boss name with its guild prefix"`
+}
+
+// DeleteCustomBoss removes a custom boss, bosses that still have records
+// can't be deleted. Deleted records count until they're purged.
+func (s *Server) DeleteCustomBoss(ctx context.Context, input *DeleteCustomBossInput) (*struct{}, error) {
+	tx, err := database.CreateTx(ctx)
+	if err != nil {
+		logging.Get().Error("Error creating transaction", "error", err)
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	defer tx.Rollback(ctx)
+
+	q := s.queries.WithTx(tx)
+
+	// New records for the boss wait until it's gone
+	if err := s.lockBosses(ctx, q, input.GuildID, input.Boss); err != nil {
+		return nil, err
+	}
+
+	count, err := q.GetCustomBossRecordCount(ctx, database.GetCustomBossRecordCountParams{
+		BossName: input.Boss,
+		GuildID:  input.GuildID,
+	})
+	if ei := database.ClassifyError(err); ei != nil {
+		if ei.Recoverable && ei.Code == "P0002" {
+			return nil, models.NewTectonicError(models.ERROR_BOSS_NOT_FOUND)
+		}
+		return nil, s.dbError(*ei)
+	}
+	if count > 0 {
+		return nil, models.NewTectonicError(models.ERROR_BOSS_IN_USE)
+	}
+
+	_, err = q.DeleteCustomBoss(ctx, database.DeleteCustomBossParams{
+		BossName: input.Boss,
+		GuildID:  input.GuildID,
+	})
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	return nil, nil
+}
+
+// checkCategoryAvailable makes sure a custom boss only uses active global
+// categories or the guild's own
+func (s *Server) checkCategoryAvailable(ctx context.Context, guildID string, category string) error {
+	ok, err := s.queries.IsCategoryAvailable(ctx, database.IsCategoryAvailableParams{
+		Category: category,
+		GuildID:  guildID,
+	})
+	if ei := database.ClassifyError(err); ei != nil {
+		return s.dbError(*ei)
+	}
+	if !ok {
+		return models.NewTectonicError(models.ERROR_CATEGORY_NOT_FOUND)
+	}
+	return nil
+}
+
+type GetCustomCategoriesInput struct {
+	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+}
+type GetCustomCategoriesOutput struct {
+	Body []database.Category
+}
+
+func (s *Server) GetCustomCategories(ctx context.Context, input *GetCustomCategoriesInput) (*GetCustomCategoriesOutput, error) {
+	categories, err := s.queries.GetGuildCustomCategories(ctx, input.GuildID)
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+	return &GetCustomCategoriesOutput{Body: categories}, nil
+}
+
+type CreateCustomCategoryInput struct {
+	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+	Body    models.CreateCustomCategoryBody
+}
+type CustomCategoryOutput struct {
+	Body database.Category
+}
+
+func (s *Server) CreateCustomCategory(ctx context.Context, input *CreateCustomCategoryInput) (*CustomCategoryOutput, error) {
+	tx, err := database.CreateTx(ctx)
+	if err != nil {
+		logging.Get().Error("Error creating transaction", "error", err)
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	defer tx.Rollback(ctx)
+
+	q := s.queries.WithTx(tx)
+
+	params := database.CreateCustomCategoryParams{
+		Name:    models.CustomName(input.GuildID, input.Body.Name),
+		Order:   int16(input.Body.Order),
+		GuildID: input.GuildID,
+	}
+	if input.Body.Thumbnail != nil {
+		params.Thumbnail = pgtype.Text{String: *input.Body.Thumbnail, Valid: true}
+	}
+	category, err := q.CreateCustomCategory(ctx, params)
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+
+	err = q.CreateGuildCategory(ctx, database.CreateGuildCategoryParams{
+		GuildID:  input.GuildID,
+		Category: category.Name,
+	})
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	return &CustomCategoryOutput{Body: category}, nil
+}
+
+type UpdateCustomCategoryInput struct {
+	GuildID  string `path:"guild_id" doc:"Guild Snowflake ID"`
+	Category string `path:"category" doc:"Category name with its guild prefix"`
+	Body     models.UpdateCustomCategoryBody
+}
+
+func (s *Server) UpdateCustomCategory(ctx context.Context, input *UpdateCustomCategoryInput) (*CustomCategoryOutput, error) {
+	params := database.UpdateCustomCategoryParams{
+		Category: input.Category,
+		GuildID:  input.GuildID,
+	}
+	if input.Body.Thumbnail != nil {
+		params.Thumbnail = pgtype.Text{String: *input.Body.Thumbnail, Valid: true}
+	}
+	if input.Body.Order != nil {
+		params.CategoryOrder = pgtype.Int2{Int16: int16(*input.Body.Order), Valid: true}
+	}
+
+	category, err := s.queries.UpdateCustomCategory(ctx, params)
+	if ei := database.ClassifyError(err); ei != nil {
+		if ei.Recoverable && ei.Code == "P0002" {
+			return nil, models.NewTectonicError(models.ERROR_CATEGORY_NOT_FOUND)
+		}
+		return nil, s.dbError(*ei)
+	}
+	return &CustomCategoryOutput{Body: category}, nil
+}
+
+type DeleteCustomCategoryInput struct {
+	GuildID  string `path:"guild_id" doc:"Guild Snowflake ID"`
+	Category string `path:"category" doc:"Category name with its guild prefix"`
+}
+
+// DeleteCustomCategory removes a custom category that no boss uses anymore
+func (s *Server) DeleteCustomCategory(ctx context.Context, input *DeleteCustomCategoryInput) (*struct{}, error) {
+	count, err := s.queries.GetCustomCategoryBossCount(ctx, database.GetCustomCategoryBossCountParams{
+		Category: input.Category,
+		GuildID:  input.GuildID,
+	})
+	if ei := database.ClassifyError(err); ei != nil {
+		if ei.Recoverable && ei.Code == "P0002" {
+			return nil, models.NewTectonicError(models.ERROR_CATEGORY_NOT_FOUND)
+		}
+		return nil, s.dbError(*ei)
+	}
+	if count > 0 {
+		return nil, models.NewTectonicError(models.ERROR_CATEGORY_IN_USE)
+	}
+
+	rows, err := s.queries.DeleteCustomCategory(ctx, database.DeleteCustomCategoryParams{
+		Category: input.Category,
+		GuildID:  input.GuildID,
+	})
+	if ei := database.ClassifyError(err); ei != nil {
+		// A boss was moved into the category in the meantime
+		if ei.Recoverable && (ei.Code == "23502" || ei.Code == "23503") {
+			return nil, models.NewTectonicError(models.ERROR_CATEGORY_IN_USE)
+		}
+		return nil, s.dbError(*ei)
+	}
+	if rows == 0 {
+		return nil, models.NewTectonicError(models.ERROR_CATEGORY_NOT_FOUND)
+	}
+	return nil, nil
+}
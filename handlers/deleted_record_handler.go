@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"tectonic-api/database"
+	"tectonic-api/logging"
+	"tectonic-api/models"
+	"tectonic-api/utils"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type GetDeletedRecordsInput struct {
+	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+}
+type GetDeletedRecordsOutput struct {
+	Body models.DeletedRecordsResponse
+}
+
+func (s *Server) GetDeletedRecords(ctx context.Context, input *GetDeletedRecordsInput) (*GetDeletedRecordsOutput, error) {
+	rows, err := s.queries.GetDeletedRecords(ctx, input.GuildID)
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+
+	formats, err := s.getValueFormats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	records := models.DeletedRecordsFromRows(rows, s.config.RecordRetention)
+	for i := range records {
+		records[i].DisplayValue = formats.format(records[i].ValueType, int(records[i].Value))
+	}
+	return &GetDeletedRecordsOutput{Body: models.DeletedRecordsResponse{Records: records}}, nil
+}
+
+type RestoreRecordInput struct {
+	GuildID  string `path:"guild_id" doc:"Guild Snowflake ID"`
+	RecordID int    `path:"record_id" doc:"Record ID"`
+}
+type RestoreRecordOutput struct {
+	Body models.RecordResponse
+}
+
+// RestoreRecord brings back a deleted record, its team was never removed so
+// it ranks exactly as it did before
+func (s *Server) RestoreRecord(ctx context.Context, input *RestoreRecordInput) (*RestoreRecordOutput, error) {
+	tx, err := database.CreateTx(ctx)
+	if err != nil {
+		logging.Get().Error("Error creating transaction", "error", err)
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	defer tx.Rollback(ctx)
+
+	q := s.queries.WithTx(tx)
+
+	if _, err := s.lockRecordBoss(ctx, q, input.GuildID, int32(input.RecordID)); err != nil {
+		return nil, err
+	}
+
+	record, err := q.RestoreRecord(ctx, database.RestoreRecordParams{
+		GuildID:  input.GuildID,
+		RecordID: int32(input.RecordID),
+	})
+	if ei := database.ClassifyError(err); ei != nil {
+		if ei.Recoverable && ei.Code == "P0002" {
+			return nil, models.NewTectonicError(models.ERROR_RECORD_NOT_FOUND)
+		}
+		return nil, s.dbError(*ei)
+	}
+
+	formats, err := s.getValueFormats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := models.RecordResponse{
+		BossName:     record.BossName,
+		Value:        int(record.Value),
+		DisplayValue: formats.format(record.ValueType, int(record.Value)),
+		RecordID:     int(record.RecordID),
+		Status:       record.Status,
+	}
+
+	// Pending and rejected records go back to where they were without a place
+	if record.Status == "approved" {
+		bossInfo, err := q.GetBossInfo(ctx, database.GetBossInfoParams{
+			BossName: record.BossName,
+			GuildID:  input.GuildID,
+		})
+		if ei := database.ClassifyError(err); ei != nil {
+			return nil, s.dbError(*ei)
+		}
+
+		settings, err := q.GetGuildRecordSettings(ctx, input.GuildID)
+		if ei := database.ClassifyError(err); ei != nil {
+			return nil, s.dbError(*ei)
+		}
+
+		allRecords, err := q.GetBossRecords(ctx, database.GetBossRecordsParams{
+			GuildID:  input.GuildID,
+			BossName: record.BossName,
+			SeasonID: record.SeasonID,
+		})
+		if ei := database.ClassifyError(err); ei != nil {
+			return nil, s.dbError(*ei)
+		}
+
+		res.ApplyPlacement(models.PlaceRecord(record.RecordID, allRecords, bossInfo, models.TiePolicy(settings.TiePolicy), int(settings.PositionCount)))
+		if res.OldValue > 0 {
+			res.DisplayOldValue = utils.FormatValue(bossValueFormat(bossInfo), res.OldValue)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	return &RestoreRecordOutput{Body: res}, nil
+}
+
+// PurgeDeletedRecords permanently removes records deleted longer than the
+// configured retention ago, along with their teams and evidence
+func (s *Server) PurgeDeletedRecords(ctx context.Context) (int64, error) {
+	return s.queries.PurgeDeletedRecords(ctx, pgtype.Interval{
+		Microseconds: s.config.RecordRetention.Microseconds(),
+		Valid:        true,
+	})
+}
+
+// RunRecordPurge purges deleted records every interval until ctx is done
+func (s *Server) RunRecordPurge(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.PurgeDeletedRecords(ctx)
+		if err != nil {
+			logging.Get().Error("Error purging deleted records", "error", err)
+		} else if purged > 0 {
+			logging.Get().Info("purged deleted records", "count", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
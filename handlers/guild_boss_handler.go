@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"tectonic-api/database"
+	"tectonic-api/logging"
+	"tectonic-api/models"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type GetGuildBossesInput struct {
+	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+}
+type GetGuildBossesOutput struct {
+	Body []models.GuildBossSettings
+}
+
+func (s *Server) GetGuildBosses(ctx context.Context, input *GetGuildBossesInput) (*GetGuildBossesOutput, error) {
+	rows, err := s.queries.GetGuildBosses(ctx, input.GuildID)
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+	return &GetGuildBossesOutput{Body: models.GuildBossSettingsFromRows(rows)}, nil
+}
+
+type UpdateGuildBossesInput struct {
+	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+	Body    models.UpdateGuildBossesBody
+}
+
+// UpdateGuildBosses enables, disables and reorders the guild's bosses in one
+// go, a boss the guild doesn't have rolls back the whole update
+func (s *Server) UpdateGuildBosses(ctx context.Context, input *UpdateGuildBossesInput) (*GetGuildBossesOutput, error) {
+	tx, err := database.CreateTx(ctx)
+	if err != nil {
+		logging.Get().Error("Error creating transaction", "error", err)
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	defer tx.Rollback(ctx)
+
+	q := s.queries.WithTx(tx)
+
+	for i, setting := range input.Body.Bosses {
+		params := database.UpdateGuildBossParams{
+			ResetOrder: setting.ResetOrder,
+			GuildID:    input.GuildID,
+			Boss:       setting.Boss,
+		}
+		if setting.Enabled != nil {
+			params.Enabled = pgtype.Bool{Bool: *setting.Enabled, Valid: true}
+		}
+		if setting.DisplayOrder != nil {
+			params.DisplayOrder = pgtype.Int2{Int16: int16(*setting.DisplayOrder), Valid: true}
+		}
+
+		rows, err := q.UpdateGuildBoss(ctx, params)
+		if ei := database.ClassifyError(err); ei != nil {
+			return nil, s.dbError(*ei)
+		}
+		if rows == 0 {
+			return nil, models.NewTectonicErrorWithDetails(models.ERROR_GUILD_BOSS_NOT_FOUND, []*huma.ErrorDetail{{
+				Location: fmt.Sprintf("body.bosses[%d].boss", i),
+				Value:    setting.Boss,
+			}})
+		}
+	}
+
+	bosses, err := q.GetGuildBosses(ctx, input.GuildID)
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	return &GetGuildBossesOutput{Body: models.GuildBossSettingsFromRows(bosses)}, nil
+}
+
+type GetGuildCategoriesInput struct {
+	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+}
+type GetGuildCategoriesOutput struct {
+	Body []models.GuildCategorySettings
+}
+
+func (s *Server) GetGuildCategories(ctx context.Context, input *GetGuildCategoriesInput) (*GetGuildCategoriesOutput, error) {
+	rows, err := s.queries.GetGuildCategories(ctx, input.GuildID)
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+	return &GetGuildCategoriesOutput{Body: models.GuildCategorySettingsFromRows(rows)}, nil
+}
+
+type UpdateGuildCategoriesInput struct {
+	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+	Body    models.UpdateGuildCategoriesBody
+}
+
+func (s *Server) UpdateGuildCategories(ctx context.Context, input *UpdateGuildCategoriesInput) (*GetGuildCategoriesOutput, error) {
+	tx, err := database.CreateTx(ctx)
+	if err != nil {
+		logging.Get().Error("Error creating transaction", "error", err)
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	defer tx.Rollback(ctx)
+
+	q := s.queries.WithTx(tx)
+
+	for i, setting := range input.Body.Categories {
+		params := database.UpdateGuildCategoryParams{
+			ResetOrder: setting.ResetOrder,
+			GuildID:    input.GuildID,
+			Category:   setting.Category,
+		}
+		if setting.Enabled != nil {
+			params.Enabled = pgtype.Bool{Bool: *setting.Enabled, Valid: true}
+		}
+		if setting.DisplayOrder != nil {
+			params.DisplayOrder = pgtype.Int2{Int16: int16(*setting.DisplayOrder), Valid: true}
+		}
+
+		rows, err := q.UpdateGuildCategory(ctx, params)
+		if ei := database.ClassifyError(err); ei != nil {
+			return nil, s.dbError(*ei)
+		}
+		if rows == 0 {
+			return nil, models.NewTectonicErrorWithDetails(models.ERROR_GUILD_CATEGORY_NOT_FOUND, []*huma.ErrorDetail{{
+				Location: fmt.Sprintf("body.categories[%d].category", i),
+				Value:    setting.Category,
+			}})
+		}
+	}
+
+	categories, err := q.GetGuildCategories(ctx, input.GuildID)
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	return &GetGuildCategoriesOutput{Body: models.GuildCategorySettingsFromRows(categories)}, nil
+}
+
+type SyncGuildBossesInput struct {
+	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+}
+type SyncGuildBossesOutput struct {
+	Body models.SyncGuildBossesResponse
+}
+
+// SyncGuildBosses adds global bosses and categories the guild doesn't have
+// yet, existing settings are left alone so disabled bosses stay disabled
+func (s *Server) SyncGuildBosses(ctx context.Context, input *SyncGuildBossesInput) (*SyncGuildBossesOutput, error) {
+	res, err := s.syncGuildBosses(ctx, pgtype.Text{String: input.GuildID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+	return &SyncGuildBossesOutput{Body: res}, nil
+}
+
+// SyncAllGuildBosses is SyncGuildBosses for every guild at once
+func (s *Server) SyncAllGuildBosses(ctx context.Context, input *struct{}) (*SyncGuildBossesOutput, error) {
+	res, err := s.syncGuildBosses(ctx, pgtype.Text{})
+	if err != nil {
+		return nil, err
+	}
+	return &SyncGuildBossesOutput{Body: res}, nil
+}
+
+// syncGuildBosses registers missing global categories and bosses to one
+// guild, or to all of them when guildID is null
+func (s *Server) syncGuildBosses(ctx context.Context, guildID pgtype.Text) (models.SyncGuildBossesResponse, error) {
+	tx, err := database.CreateTx(ctx)
+	if err != nil {
+		logging.Get().Error("Error creating transaction", "error", err)
+		return models.SyncGuildBossesResponse{}, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	defer tx.Rollback(ctx)
+
+	res, err := s.syncGuildCatalog(ctx, s.queries.WithTx(tx), guildID)
+	if err != nil {
+		return res, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return res, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	return res, nil
+}
+
+// syncGuildCatalog does the sync within the caller's transaction, categories
+// go first so the guild has them before their bosses
+func (s *Server) syncGuildCatalog(ctx context.Context, q *database.Queries, guildID pgtype.Text) (models.SyncGuildBossesResponse, error) {
+	var res models.SyncGuildBossesResponse
+	var err error
+
+	res.CategoriesAdded, err = q.SyncGuildCategories(ctx, guildID)
+	if ei := database.ClassifyError(err); ei != nil {
+		return res, s.dbError(*ei)
+	}
+
+	res.BossesAdded, err = q.SyncGuildBosses(ctx, guildID)
+	if ei := database.ClassifyError(err); ei != nil {
+		return res, s.dbError(*ei)
+	}
+	return res, nil
+}
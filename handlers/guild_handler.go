@@ -15,6 +15,7 @@ import (
 type GetGuildInput struct {
 	GuildID  models.DiscordSnowflake `path:"guild_id" doc:"Guild Snowflake ID"`
 	Detailed bool                    `query:"detailed" default:"false" doc:"Fetch detailed guild information"`
+	Season   int32                   `query:"season" minimum:"0" doc:"Season ID for detailed records, defaults to the current season"`
 }
 
 type GetGuildOutput struct {
@@ -23,11 +24,24 @@ type GetGuildOutput struct {
 
 func (s *Server) GetGuild(ctx context.Context, input *GetGuildInput) (*GetGuildOutput, error) {
 	if input.Detailed {
-		row, err := s.queries.GetDetailedGuild(ctx, string(input.GuildID))
+		season, err := s.getSeason(ctx, string(input.GuildID), input.Season)
+		if err != nil {
+			return nil, err
+		}
+		row, err := s.queries.GetDetailedGuild(ctx, database.GetDetailedGuildParams{
+			GuildID:  string(input.GuildID),
+			SeasonID: season.SeasonID,
+		})
 		if ei := database.ClassifyError(err); ei != nil {
 			return nil, s.dbError(*ei)
 		}
+		formats, err := s.getValueFormats(ctx)
+		if err != nil {
+			return nil, err
+		}
+
 		guild := models.GuildResponseFromDetailedRow(row)
+		formatGuildRecords(formats, guild.Records)
 		return &GetGuildOutput{Body: guild}, nil
 	}
 
@@ -123,12 +137,19 @@ func (s *Server) UpdateGuild(ctx context.Context, input *UpdateGuildInput) (*str
 		positionCount = int16(*input.Body.PositionCount)
 	}
 
+	var seasonalPoints pgtype.Bool
+	if input.Body.SeasonalPoints != nil {
+		seasonalPoints = pgtype.Bool{Bool: *input.Body.SeasonalPoints, Valid: true}
+	}
+
 	_, err = q.UpdateGuild(ctx, database.UpdateGuildParams{
-		Multiplier:    multiplier,
-		PbChannelID:   pbChannelID,
-		ModChannelID:  modChannelID,
-		PositionCount: positionCount,
-		GuildID:       input.GuildID,
+		Multiplier:     multiplier,
+		PbChannelID:    pbChannelID,
+		ModChannelID:   modChannelID,
+		PositionCount:  positionCount,
+		TiePolicy:      utils.DerefOr(input.Body.TiePolicy, ""),
+		SeasonalPoints: seasonalPoints,
+		GuildID:        input.GuildID,
 	})
 	if ei := database.ClassifyError(err); ei != nil {
 		return nil, s.dbError(*ei)
@@ -119,3 +119,48 @@ func (s *Server) DeleteGuildRank(ctx context.Context, input *DeleteGuildRankInpu
 	}
 	return nil, nil
 }
+
+type GetRankAssignmentsInput struct {
+	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+}
+type RankAssignmentsOutput struct {
+	Body []models.RankAssignment
+}
+
+// GetRankAssignments returns the rank role every user should hold
+func (s *Server) GetRankAssignments(ctx context.Context, input *GetRankAssignmentsInput) (*RankAssignmentsOutput, error) {
+	assignments, _, ei := s.getRankAssignments(ctx, input.GuildID)
+	if ei != nil {
+		return nil, s.dbError(*ei)
+	}
+	return &RankAssignmentsOutput{Body: assignments}, nil
+}
+
+type DiffRankAssignmentsInput struct {
+	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+	Body    models.RankAssignmentsBody
+}
+
+// DiffRankAssignments compares the roles members currently hold against
+// their rank and returns the roles to add and remove for members out of sync
+func (s *Server) DiffRankAssignments(ctx context.Context, input *DiffRankAssignmentsInput) (*RankAssignmentsOutput, error) {
+	assignments, ranks, ei := s.getRankAssignments(ctx, input.GuildID)
+	if ei != nil {
+		return nil, s.dbError(*ei)
+	}
+	return &RankAssignmentsOutput{Body: models.RankRoleDiff(assignments, input.Body.Members, ranks)}, nil
+}
+
+func (s *Server) getRankAssignments(ctx context.Context, guildID string) ([]models.RankAssignment, []database.GetGuildRanksRow, *database.ErrorInfo) {
+	ranks, ei := database.WrapQuery(s.queries.GetGuildRanks, ctx, guildID)
+	if ei != nil {
+		return nil, nil, ei
+	}
+
+	users, ei := database.WrapQuery(s.queries.GetGuildUserPoints, ctx, guildID)
+	if ei != nil {
+		return nil, nil, ei
+	}
+
+	return models.RankAssignmentsFromRows(users, ranks), ranks, nil
+}
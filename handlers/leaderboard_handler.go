@@ -11,6 +11,7 @@ import (
 type GetLeaderboardInput struct {
 	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
 	Limit   int32  `query:"limit" default:"50" minimum:"1" maximum:"1000" doc:"Maximum number of users to return"`
+	Season  int32  `query:"season" minimum:"0" doc:"Season ID, defaults to the current season. All-time points are shown for the current season unless the guild uses seasonal points"`
 }
 type GetLeaderboardOutput struct {
 	Body []models.LeaderboardUser
@@ -29,10 +30,33 @@ func (s *Server) GetLeaderboard(ctx context.Context, input *GetLeaderboardInput)
 		UserLimit: limit,
 	}
 
-	logging.Get().DebugContext(ctx, "querying leaderboard from database", "guild_id", params.GuildID, "user_limit", params.UserLimit)
-	rows, err := s.queries.GetLeaderboard(ctx, params)
-	if ei := database.ClassifyError(err); ei != nil {
-		return nil, s.dbError(*ei)
+	season, err := s.getSeason(ctx, input.GuildID, input.Season)
+	if err != nil {
+		return nil, err
+	}
+
+	// The current season shows all-time points whether it was asked for by
+	// id or not
+	var rows []database.GetLeaderboardRow
+	if !season.EndedAt.Valid && !season.SeasonalPoints {
+		logging.Get().DebugContext(ctx, "querying leaderboard from database", "guild_id", params.GuildID, "user_limit", params.UserLimit)
+		rows, err = s.queries.GetLeaderboard(ctx, params)
+		if ei := database.ClassifyError(err); ei != nil {
+			return nil, s.dbError(*ei)
+		}
+	} else {
+		logging.Get().DebugContext(ctx, "querying season leaderboard from database", "guild_id", params.GuildID, "season_id", season.SeasonID, "user_limit", params.UserLimit)
+		seasonRows, err := s.queries.GetSeasonLeaderboard(ctx, database.GetSeasonLeaderboardParams{
+			SeasonID:  season.SeasonID,
+			GuildID:   params.GuildID,
+			UserLimit: params.UserLimit,
+		})
+		if ei := database.ClassifyError(err); ei != nil {
+			return nil, s.dbError(*ei)
+		}
+		for _, row := range seasonRows {
+			rows = append(rows, database.GetLeaderboardRow(row))
+		}
 	}
 
 	if len(rows) == 0 {
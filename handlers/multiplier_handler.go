@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"context"
+
+	"tectonic-api/database"
+	"tectonic-api/models"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type GetMultiplierWindowsInput struct {
+	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+}
+type GetMultiplierWindowsOutput struct {
+	Body []models.MultiplierWindow
+}
+
+// GetMultiplierWindows returns the guild's current and upcoming multiplier windows
+func (s *Server) GetMultiplierWindows(ctx context.Context, input *GetMultiplierWindowsInput) (*GetMultiplierWindowsOutput, error) {
+	rows, ei := database.WrapQuery(s.queries.GetGuildMultiplierWindows, ctx, input.GuildID)
+	if ei != nil {
+		return nil, s.dbError(*ei)
+	}
+	return &GetMultiplierWindowsOutput{Body: models.MultiplierWindowsFromRows(rows)}, nil
+}
+
+type CreateMultiplierWindowInput struct {
+	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+	Body    models.CreateMultiplierWindowBody
+}
+type CreateMultiplierWindowOutput struct {
+	Body models.MultiplierWindow
+}
+
+func (s *Server) CreateMultiplierWindow(ctx context.Context, input *CreateMultiplierWindowInput) (*CreateMultiplierWindowOutput, error) {
+	row, err := s.queries.CreateGuildMultiplierWindow(ctx, database.CreateGuildMultiplierWindowParams{
+		GuildID:    input.GuildID,
+		Name:       input.Body.Name,
+		Multiplier: int32(input.Body.Multiplier),
+		StartsAt:   pgtype.Timestamptz{Time: input.Body.StartsAt, Valid: true},
+		EndsAt:     pgtype.Timestamptz{Time: input.Body.EndsAt, Valid: true},
+	})
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+
+	return &CreateMultiplierWindowOutput{Body: models.MultiplierWindowFromRow(database.GetGuildMultiplierWindowsRow(row))}, nil
+}
+
+type DeleteMultiplierWindowInput struct {
+	GuildID  string `path:"guild_id" doc:"Guild Snowflake ID"`
+	WindowID int    `path:"window_id" doc:"Multiplier window ID"`
+}
+
+func (s *Server) DeleteMultiplierWindow(ctx context.Context, input *DeleteMultiplierWindowInput) (*struct{}, error) {
+	rows, err := s.queries.DeleteGuildMultiplierWindow(ctx, database.DeleteGuildMultiplierWindowParams{
+		GuildID:  input.GuildID,
+		WindowID: int32(input.WindowID),
+	})
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+
+	if rows == 0 {
+		return nil, models.NewTectonicError(models.ERROR_MULTIPLIER_WINDOW_NOT_FOUND)
+	}
+	return nil, nil
+}
@@ -6,6 +6,8 @@ import (
 
 	"tectonic-api/database"
 	"tectonic-api/models"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type UpdatePointsInput struct {
@@ -14,7 +16,7 @@ type UpdatePointsInput struct {
 	PointEvent string `path:"point_event" doc:"Point event name"`
 }
 type UpdatePointsOutput struct {
-	Body []database.UpdatePointsByEventRow
+	Body []models.UserPointsUpdate
 }
 
 func (s *Server) UpdatePoints(ctx context.Context, input *UpdatePointsInput) (*UpdatePointsOutput, error) {
@@ -35,7 +37,12 @@ func (s *Server) UpdatePoints(ctx context.Context, input *UpdatePointsInput) (*U
 	if len(user) == 0 {
 		return nil, models.NewTectonicError(models.ERROR_POINT_SOURCE_NOT_FOUND)
 	}
-	return &UpdatePointsOutput{Body: user}, nil
+
+	ranks, ei := database.WrapQuery(s.queries.GetGuildRanks, ctx, input.GuildID)
+	if ei != nil {
+		return nil, s.dbError(*ei)
+	}
+	return &UpdatePointsOutput{Body: models.PointsUpdatesFromEventRows(user, ranks)}, nil
 }
 
 type UpdatePointsCustomInput struct {
@@ -44,7 +51,7 @@ type UpdatePointsCustomInput struct {
 	Points  int    `path:"points" doc:"Points to add"`
 }
 type UpdatePointsCustomOutput struct {
-	Body []database.UpdatePointsCustomRow
+	Body []models.UserPointsUpdate
 }
 
 func (s *Server) UpdatePointsCustom(ctx context.Context, input *UpdatePointsCustomInput) (*UpdatePointsCustomOutput, error) {
@@ -62,7 +69,12 @@ func (s *Server) UpdatePointsCustom(ctx context.Context, input *UpdatePointsCust
 	if len(user) == 0 {
 		return nil, models.NewTectonicError(models.ERROR_POINT_SOURCE_NOT_FOUND)
 	}
-	return &UpdatePointsCustomOutput{Body: user}, nil
+
+	ranks, ei := database.WrapQuery(s.queries.GetGuildRanks, ctx, input.GuildID)
+	if ei != nil {
+		return nil, s.dbError(*ei)
+	}
+	return &UpdatePointsCustomOutput{Body: models.PointsUpdatesFromCustomRows(user, ranks)}, nil
 }
 
 type GetPointSourcesInput struct {
@@ -103,3 +115,215 @@ func (s *Server) UpdateGuildPointSource(ctx context.Context, input *UpdateGuildP
 	}
 	return nil, nil
 }
+
+type GetPointsHistoryInput struct {
+	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+	UserID  string `path:"user_id" doc:"User Snowflake ID"`
+	Limit   int32  `query:"limit" default:"50" minimum:"1" maximum:"1000" doc:"Maximum number of transactions to return"`
+	Offset  int32  `query:"offset" default:"0" minimum:"0" doc:"Number of transactions to skip"`
+}
+type GetPointsHistoryOutput struct {
+	Body []models.PointTransaction
+}
+
+func (s *Server) GetPointsHistory(ctx context.Context, input *GetPointsHistoryInput) (*GetPointsHistoryOutput, error) {
+	rows, err := s.queries.GetUserPointTransactions(ctx, database.GetUserPointTransactionsParams{
+		GuildID:           input.GuildID,
+		UserID:            input.UserID,
+		TransactionLimit:  input.Limit,
+		TransactionOffset: input.Offset,
+	})
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+	return &GetPointsHistoryOutput{Body: models.PointTransactionsFromRows(rows)}, nil
+}
+
+type VerifyPointsInput struct {
+	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+}
+type VerifyPointsOutput struct {
+	Body []database.GetPointsLedgerMismatchesRow
+}
+
+// VerifyPoints lists users whose points don't match the sum of their ledger
+func (s *Server) VerifyPoints(ctx context.Context, input *VerifyPointsInput) (*VerifyPointsOutput, error) {
+	rows, ei := database.WrapQuery(s.queries.GetPointsLedgerMismatches, ctx, input.GuildID)
+	if ei != nil {
+		return nil, s.dbError(*ei)
+	}
+	if rows == nil {
+		rows = []database.GetPointsLedgerMismatchesRow{}
+	}
+	return &VerifyPointsOutput{Body: rows}, nil
+}
+
+type RebuildPointsInput struct {
+	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+}
+type RebuildPointsOutput struct {
+	Body struct {
+		UsersUpdated int64 `json:"users_updated"`
+	}
+}
+
+// RebuildPoints resets every user's points to the sum of their ledger
+func (s *Server) RebuildPoints(ctx context.Context, input *RebuildPointsInput) (*RebuildPointsOutput, error) {
+	rows, ei := database.WrapQuery(s.queries.RebuildPointsFromLedger, ctx, input.GuildID)
+	if ei != nil {
+		return nil, s.dbError(*ei)
+	}
+
+	out := &RebuildPointsOutput{}
+	out.Body.UsersUpdated = rows
+	return out, nil
+}
+
+type RevokePointTransactionInput struct {
+	GuildID       string `path:"guild_id" doc:"Guild Snowflake ID"`
+	TransactionID int    `path:"transaction_id" doc:"Point transaction ID"`
+	Body          models.RevokePointsBody
+}
+type RevokePointBatchInput struct {
+	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+	BatchID int    `path:"batch_id" doc:"Point transaction batch ID"`
+	Body    models.RevokePointsBody
+}
+type RevokePointsOutput struct {
+	Body []database.RevokePointTransactionsRow
+}
+
+func (s *Server) RevokePointTransaction(ctx context.Context, input *RevokePointTransactionInput) (*RevokePointsOutput, error) {
+	return s.revokePoints(ctx, database.GetRevocablePointTransactionsParams{
+		GuildID:       input.GuildID,
+		TransactionID: pgtype.Int4{Int32: int32(input.TransactionID), Valid: true},
+	}, input.Body)
+}
+
+func (s *Server) RevokePointBatch(ctx context.Context, input *RevokePointBatchInput) (*RevokePointsOutput, error) {
+	return s.revokePoints(ctx, database.GetRevocablePointTransactionsParams{
+		GuildID: input.GuildID,
+		BatchID: pgtype.Int4{Int32: int32(input.BatchID), Valid: true},
+	}, input.Body)
+}
+
+// revokePoints reverses every matched transaction with a compensating ledger
+// entry, either all of them are revoked or none are
+func (s *Server) revokePoints(ctx context.Context, params database.GetRevocablePointTransactionsParams, body models.RevokePointsBody) (*RevokePointsOutput, error) {
+	tx, err := database.CreateTx(ctx)
+	if err != nil {
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	defer tx.Rollback(ctx)
+
+	q := s.queries.WithTx(tx)
+
+	transactions, err := q.GetRevocablePointTransactions(ctx, params)
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+
+	if len(transactions) == 0 {
+		return nil, models.NewTectonicError(models.ERROR_POINT_TRANSACTION_NOT_FOUND)
+	}
+
+	ids := make([]int32, len(transactions))
+	for i, t := range transactions {
+		if t.RevokedAt.Valid {
+			return nil, models.NewTectonicError(models.ERROR_POINT_TRANSACTION_REVOKED)
+		}
+		ids[i] = t.TransactionID
+	}
+
+	revoked, err := q.RevokePointTransactions(ctx, database.RevokePointTransactionsParams{
+		RevokedBy:      pgtype.Text{String: string(body.RevokedBy), Valid: true},
+		RevokeReason:   pgtype.Text{String: body.Reason, Valid: true},
+		GuildID:        params.GuildID,
+		TransactionIds: ids,
+	})
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	return &RevokePointsOutput{Body: revoked}, nil
+}
+
+type CreatePointSourceInput struct {
+	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+	Body    models.CreatePointSourceBody
+}
+type PointSourceOutput struct {
+	Body database.GetGuildPointSourcesRow
+}
+
+func (s *Server) CreatePointSource(ctx context.Context, input *CreatePointSourceInput) (*PointSourceOutput, error) {
+	row, err := s.queries.CreateGuildPointSource(ctx, database.CreateGuildPointSourceParams{
+		GuildID: input.GuildID,
+		Source:  input.Body.Source,
+		Points:  int32(input.Body.Points),
+		Name:    input.Body.Name,
+	})
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+	return &PointSourceOutput{Body: database.GetGuildPointSourcesRow(row)}, nil
+}
+
+type EditPointSourceInput struct {
+	GuildID     string `path:"guild_id" doc:"Guild Snowflake ID"`
+	PointSource string `path:"point_source" doc:"Point source name"`
+	Body        models.UpdatePointSourceBody
+}
+
+func (s *Server) EditPointSource(ctx context.Context, input *EditPointSourceInput) (*PointSourceOutput, error) {
+	var name pgtype.Text
+	if input.Body.Name != nil {
+		name = pgtype.Text{String: *input.Body.Name, Valid: true}
+	}
+
+	var points pgtype.Int4
+	if input.Body.Points != nil {
+		points = pgtype.Int4{Int32: int32(*input.Body.Points), Valid: true}
+	}
+
+	row, err := s.queries.EditGuildPointSource(ctx, database.EditGuildPointSourceParams{
+		Name:        name,
+		Points:      points,
+		GuildID:     input.GuildID,
+		PointSource: input.PointSource,
+	})
+	if ei := database.ClassifyError(err); ei != nil {
+		if ei.Recoverable && ei.Code == "P0002" {
+			return nil, models.NewTectonicError(models.ERROR_POINT_SOURCE_NOT_FOUND)
+		}
+		return nil, s.dbError(*ei)
+	}
+	return &PointSourceOutput{Body: database.GetGuildPointSourcesRow(row)}, nil
+}
+
+type DeletePointSourceInput struct {
+	GuildID     string `path:"guild_id" doc:"Guild Snowflake ID"`
+	PointSource string `path:"point_source" doc:"Point source name"`
+}
+
+func (s *Server) DeletePointSource(ctx context.Context, input *DeletePointSourceInput) (*struct{}, error) {
+	rows, err := s.queries.DeleteGuildPointSource(ctx, database.DeleteGuildPointSourceParams{
+		GuildID:     input.GuildID,
+		PointSource: input.PointSource,
+	})
+	if ei := database.ClassifyError(err); ei != nil {
+		// Combat achievements still reference this point source
+		if ei.Recoverable && ei.Code == "23503" {
+			return nil, models.NewTectonicError(models.ERROR_POINT_SOURCE_IN_USE)
+		}
+		return nil, s.dbError(*ei)
+	}
+
+	if rows == 0 {
+		return nil, models.NewTectonicError(models.ERROR_POINT_SOURCE_NOT_FOUND)
+	}
+	return nil, nil
+}
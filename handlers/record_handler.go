@@ -2,32 +2,97 @@ package handlers
 
 import (
 	"context"
-	"sort"
+	"fmt"
+	"slices"
 	"time"
 
 	"tectonic-api/database"
 	"tectonic-api/logging"
 	"tectonic-api/models"
+	"tectonic-api/utils"
 
+	"github.com/danielgtaylor/huma/v2"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type GetGuildRecordsInput struct {
 	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+	Season  int32  `query:"season" minimum:"0" doc:"Season ID, defaults to the current season"`
 }
 type GetGuildRecordsOutput struct {
 	Body models.GuildResponse
 }
 
 func (s *Server) GetGuildRecords(ctx context.Context, input *GetGuildRecordsInput) (*GetGuildRecordsOutput, error) {
-	row, err := s.queries.GetDetailedGuild(ctx, input.GuildID)
+	season, err := s.getSeason(ctx, input.GuildID, input.Season)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := s.queries.GetDetailedGuild(ctx, database.GetDetailedGuildParams{
+		GuildID:  input.GuildID,
+		SeasonID: season.SeasonID,
+	})
 	if ei := database.ClassifyError(err); ei != nil {
 		return nil, s.dbError(*ei)
 	}
+	formats, err := s.getValueFormats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	guild := models.GuildResponseFromDetailedRow(row)
+	formatGuildRecords(formats, guild.Records)
 	return &GetGuildRecordsOutput{Body: guild}, nil
 }
 
+type GetBossRankingInput struct {
+	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+	Boss    string `path:"boss" doc:"Boss name"`
+	Limit   int32  `query:"limit" default:"50" minimum:"1" maximum:"1000" doc:"Maximum number of entries to return"`
+	Offset  int32  `query:"offset" default:"0" minimum:"0" doc:"Number of entries to skip"`
+	Season  int32  `query:"season" minimum:"0" doc:"Season ID, defaults to the current season"`
+}
+type GetBossRankingOutput struct {
+	Body models.BossRanking
+}
+
+// GetBossRanking returns the complete ranking for a boss, not just the
+// positions shown on the guild board
+func (s *Server) GetBossRanking(ctx context.Context, input *GetBossRankingInput) (*GetBossRankingOutput, error) {
+	bossInfo, err := s.queries.GetBossInfo(ctx, database.GetBossInfoParams{
+		BossName: input.Boss,
+		GuildID:  input.GuildID,
+	})
+	if ei := database.ClassifyError(err); ei != nil {
+		if ei.Recoverable && ei.Code == "P0002" {
+			return nil, models.NewTectonicError(models.ERROR_BOSS_NOT_FOUND)
+		}
+		return nil, s.dbError(*ei)
+	}
+
+	season, err := s.getSeason(ctx, input.GuildID, input.Season)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.queries.GetBossRanking(ctx, database.GetBossRankingParams{
+		GuildID:      input.GuildID,
+		BossName:     input.Boss,
+		SeasonID:     season.SeasonID,
+		RecordLimit:  input.Limit,
+		RecordOffset: input.Offset,
+	})
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+	ranking := models.BossRankingFromRows(bossInfo, rows)
+	for i := range ranking.Entries {
+		ranking.Entries[i].DisplayValue = utils.FormatValue(bossValueFormat(bossInfo), int(ranking.Entries[i].Value))
+	}
+	return &GetBossRankingOutput{Body: ranking}, nil
+}
+
 // Teams
 
 type AddTeammateByBossInput struct {
@@ -123,19 +188,55 @@ type CreateRecordOutput struct {
 }
 
 func (s *Server) CreateRecord(ctx context.Context, input *CreateRecordInput) (*CreateRecordOutput, error) {
-	res := models.RecordResponse{
+	// Verify boss exists and get its info
+	bossInfo, err := s.queries.GetBossInfo(ctx, database.GetBossInfoParams{
 		BossName: input.Body.BossName,
-		Value:    input.Body.Value,
+		GuildID:  input.GuildID,
+	})
+	if ei := database.ClassifyError(err); ei != nil {
+		if ei.Recoverable && ei.Code == "P0002" {
+			return nil, models.NewTectonicError(models.ERROR_GUILD_BOSS_NOT_FOUND)
+		}
+		return nil, s.dbError(*ei)
+	}
+	if bossInfo.Retired {
+		return nil, models.NewTectonicError(models.ERROR_BOSS_RETIRED)
+	}
+	if !bossInfo.Enabled {
+		return nil, models.NewTectonicError(models.ERROR_GUILD_BOSS_DISABLED)
 	}
 
-	// Verify boss exists and get its info
-	bossInfo, err := s.queries.GetBossInfo(ctx, input.Body.BossName)
+	value, err := recordValue(input.Body.Value, input.Body.Time, bossValueFormat(bossInfo))
+	if err != nil {
+		return nil, err
+	}
+
+	date := time.Now()
+	if input.Body.Date != nil {
+		date = *input.Body.Date
+	}
+	if err := checkRecordDate(date, bossInfo.ReleasedAt); err != nil {
+		return nil, err
+	}
+
+	res := models.RecordResponse{
+		BossName:     input.Body.BossName,
+		Value:        value,
+		DisplayValue: utils.FormatValue(bossValueFormat(bossInfo), value),
+		Status:       "approved",
+	}
+
+	// Guilds with a mod channel review submissions before they are ranked
+	settings, err := s.queries.GetGuildRecordSettings(ctx, input.GuildID)
 	if ei := database.ClassifyError(err); ei != nil {
 		if ei.Recoverable && ei.Code == "P0002" {
-			return nil, models.NewTectonicError(models.ERROR_GUILD_BOSS_NOT_FOUND)
+			return nil, models.NewTectonicError(models.ERROR_GUILD_NOT_FOUND)
 		}
 		return nil, s.dbError(*ei)
 	}
+	if settings.ModChannelID.Valid {
+		res.Status = "pending"
+	}
 
 	tx, err := database.CreateTx(ctx)
 	if err != nil {
@@ -146,16 +247,28 @@ func (s *Server) CreateRecord(ctx context.Context, input *CreateRecordInput) (*C
 
 	q := s.queries.WithTx(tx)
 
+	// Submissions for the same boss are placed one at a time
+	if err := s.lockBosses(ctx, q, input.GuildID, bossInfo.Name); err != nil {
+		return nil, err
+	}
+
 	// Always insert the record
-	recordID, err := q.CreateRecord(ctx, database.CreateRecordParams{
-		Value:    int32(input.Body.Value),
+	params := database.CreateRecordParams{
+		Value:    int32(value),
 		BossName: input.Body.BossName,
-		Date:     pgtype.Timestamp{Time: time.Now(), Valid: true},
+		Date:     pgtype.Timestamp{Time: date, Valid: true},
 		GuildID:  input.GuildID,
-	})
+		Status:   res.Status,
+	}
+	if input.Body.SubmittedBy != nil {
+		params.SubmittedBy = pgtype.Text{String: string(*input.Body.SubmittedBy), Valid: true}
+	}
+	created, err := q.CreateRecord(ctx, params)
 	if ei := database.ClassifyError(err); ei != nil {
 		return nil, s.dbError(*ei)
 	}
+	recordID := created.RecordID
+	res.RecordID = int(recordID)
 
 	// Create team entries
 	err = q.CreateTeam(ctx, database.CreateTeamParams{
@@ -167,10 +280,33 @@ func (s *Server) CreateRecord(ctx context.Context, input *CreateRecordInput) (*C
 		return nil, s.dbError(*ei)
 	}
 
+	if len(input.Body.Evidence) > 0 {
+		kinds, urls, submittedBy := models.EvidenceColumns(input.Body.Evidence)
+		_, err = q.CreateRecordEvidence(ctx, database.CreateRecordEvidenceParams{
+			Kinds:       kinds,
+			Urls:        urls,
+			SubmittedBy: submittedBy,
+			GuildID:     input.GuildID,
+			RecordID:    recordID,
+		})
+		if ei := database.ClassifyError(err); ei != nil {
+			return nil, s.dbError(*ei)
+		}
+	}
+
+	// Pending records are placed once a moderator approves them
+	if res.Status == "pending" {
+		if err = tx.Commit(ctx); err != nil {
+			return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+		}
+		return &CreateRecordOutput{Body: res}, nil
+	}
+
 	// Get all records for this boss to determine position
 	allRecords, err := q.GetBossRecords(ctx, database.GetBossRecordsParams{
 		GuildID:  input.GuildID,
 		BossName: input.Body.BossName,
+		SeasonID: created.SeasonID,
 	})
 	if ei := database.ClassifyError(err); ei != nil {
 		return nil, s.dbError(*ei)
@@ -180,178 +316,595 @@ func (s *Server) CreateRecord(ctx context.Context, input *CreateRecordInput) (*C
 		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
 	}
 
-	// Compute position in Go
-	position := computeRecordPosition(recordID, allRecords, bossInfo)
-	res.RecordID = int(recordID)
-	if position > 0 {
-		res.Position = &position
+	// Compare the board with and without the new record
+	placement := models.PlaceRecord(recordID, allRecords, bossInfo, models.TiePolicy(settings.TiePolicy), int(settings.PositionCount))
+	res.ApplyPlacement(placement)
+	if res.OldValue > 0 {
+		res.DisplayOldValue = utils.FormatValue(bossValueFormat(bossInfo), res.OldValue)
 	}
 
-	// Find the previous value at this position (if any) for the response
-	oldValue := findOldValueAtPosition(recordID, allRecords, bossInfo, position)
-	res.OldValue = oldValue
-
 	return &CreateRecordOutput{Body: res}, nil
 }
 
-// computeRecordPosition determines where a record ranks among all records for a boss.
-// For solo bosses, deduplicates by user (best per user).
-// Returns the 1-based position, or 0 if the record is not in the ranking
-// (e.g., the user has a better record for a solo boss).
-func computeRecordPosition(recordID int32, rows []database.GetBossRecordsRow, bossInfo database.GetBossInfoRow) int {
-	type recordEntry struct {
-		RecordID int32
-		Value    int32
-		UserID   string // first user (for solo dedup)
-	}
-
-	// Group rows by record_id
-	recordMap := make(map[int32]*recordEntry)
-	var recordOrder []int32
-	for _, row := range rows {
-		if _, ok := recordMap[row.RecordID]; !ok {
-			recordMap[row.RecordID] = &recordEntry{
-				RecordID: row.RecordID,
-				Value:    row.Value,
-				UserID:   row.UserID,
-			}
-			recordOrder = append(recordOrder, row.RecordID)
-		}
+// recordValue resolves the submitted value, human readable times are only
+// accepted for time based bosses and are stored in whole steps. Either way
+// the value has to fit the bounds of the boss's value type.
+func recordValue(value int, t string, format utils.ValueFormat) (int, error) {
+	location, submitted := "body.value", any(value)
+	var err error
+	if t != "" {
+		location, submitted = "body.time", t
+		value, err = format.ParseTime(t)
+	}
+	if err == nil {
+		err = format.Validate(value)
 	}
+	if err != nil {
+		return 0, models.NewTectonicErrorWithDetails(models.ERROR_VALIDATION_FAILED, []*huma.ErrorDetail{{
+			Location: location,
+			Message:  err.Error(),
+			Value:    submitted,
+		}})
+	}
+	return value, nil
+}
 
-	var eligible []recordEntry
+// lockBosses serializes ranking changes of the given bosses until the
+// transaction ends. Bosses are locked in name order so two transactions
+// touching the same bosses can't deadlock each other.
+func (s *Server) lockBosses(ctx context.Context, q *database.Queries, guildID string, bossNames ...string) error {
+	bossNames = slices.Clone(bossNames)
+	slices.Sort(bossNames)
+	for _, bossName := range slices.Compact(bossNames) {
+		err := q.LockGuildBoss(ctx, database.LockGuildBossParams{
+			GuildID:  guildID,
+			BossName: bossName,
+		})
+		if ei := database.ClassifyError(err); ei != nil {
+			return s.dbError(*ei)
+		}
+	}
+	return nil
+}
 
-	if bossInfo.Solo {
-		// For solo bosses: pick each user's best record
-		bestByUser := make(map[string]*recordEntry)
-		for _, rid := range recordOrder {
-			entry := recordMap[rid]
-			existing, ok := bestByUser[entry.UserID]
-			if !ok || isBetter(entry.Value, existing.Value, bossInfo.HigherIsBetter) {
-				bestByUser[entry.UserID] = entry
+// lockRecordBoss locks the boss a record belongs to, along with any other
+// bosses, before the record itself is touched. The record can move to another
+// boss while we wait, so the lookup is repeated until it is stable.
+func (s *Server) lockRecordBoss(ctx context.Context, q *database.Queries, guildID string, recordID int32, others ...string) (string, error) {
+	params := database.GetRecordBossParams{
+		GuildID:  guildID,
+		RecordID: recordID,
+	}
+	bossName, err := q.GetRecordBoss(ctx, params)
+	for {
+		if ei := database.ClassifyError(err); ei != nil {
+			if ei.Recoverable && ei.Code == "P0002" {
+				return "", models.NewTectonicError(models.ERROR_RECORD_NOT_FOUND)
 			}
+			return "", s.dbError(*ei)
 		}
-		for _, entry := range bestByUser {
-			eligible = append(eligible, *entry)
+		if err := s.lockBosses(ctx, q, guildID, slices.Concat(others, []string{bossName})...); err != nil {
+			return "", err
 		}
-	} else {
-		// For team bosses: all records are eligible
-		for _, rid := range recordOrder {
-			eligible = append(eligible, *recordMap[rid])
+
+		var current string
+		current, err = q.GetRecordBoss(ctx, params)
+		if err == nil && current == bossName {
+			return bossName, nil
 		}
+		bossName = current
 	}
+}
 
-	// Sort by value (respecting higher_is_better)
-	sort.Slice(eligible, func(i, j int) bool {
-		return isBetter(eligible[i].Value, eligible[j].Value, bossInfo.HigherIsBetter)
-	})
+// recordDateError explains why a record can't have been achieved at date, an
+// empty string means the date is fine
+func recordDateError(date time.Time, releasedAt pgtype.Date) string {
+	if date.After(time.Now()) {
+		return "date can't be in the future"
+	}
+	if releasedAt.Valid && date.Before(releasedAt.Time) {
+		return fmt.Sprintf("date can't be before the boss was released on %s", releasedAt.Time.Format(time.DateOnly))
+	}
+	return ""
+}
 
-	// Find position of the target record
-	for i, entry := range eligible {
-		if entry.RecordID == recordID {
-			return i + 1 // 1-based
-		}
+func checkRecordDate(date time.Time, releasedAt pgtype.Date) error {
+	if reason := recordDateError(date, releasedAt); reason != "" {
+		return models.NewTectonicErrorWithDetails(models.ERROR_VALIDATION_FAILED, []*huma.ErrorDetail{{
+			Location: "body.date",
+			Message:  reason,
+			Value:    date,
+		}})
 	}
-	return 0
+	return nil
 }
 
-// findOldValueAtPosition finds what value was previously at the given position
-// before the new record was inserted.
-func findOldValueAtPosition(newRecordID int32, rows []database.GetBossRecordsRow, bossInfo database.GetBossInfoRow, position int) int {
-	if position <= 0 {
-		return 0
+type UpdateRecordInput struct {
+	GuildID  string `path:"guild_id" doc:"Guild Snowflake ID"`
+	RecordID int    `path:"record_id" doc:"Record ID"`
+	Body     models.UpdateRecordBody
+}
+type UpdateRecordOutput struct {
+	Body models.UpdateRecordResponse
+}
+
+// UpdateRecord edits a record in place, keeping its id, and re-ranks it
+func (s *Server) UpdateRecord(ctx context.Context, input *UpdateRecordInput) (*UpdateRecordOutput, error) {
+	tx, err := database.CreateTx(ctx)
+	if err != nil {
+		logging.Get().Error("Error creating transaction", "error", err)
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
 	}
+	defer tx.Rollback(ctx)
+
+	q := s.queries.WithTx(tx)
 
-	type recordEntry struct {
-		RecordID int32
-		Value    int32
-		UserID   string
+	var bossNames []string
+	if input.Body.BossName != nil {
+		bossNames = append(bossNames, *input.Body.BossName)
+	}
+	if _, err := s.lockRecordBoss(ctx, q, input.GuildID, int32(input.RecordID), bossNames...); err != nil {
+		return nil, err
 	}
 
-	// Build entries excluding the new record
-	recordMap := make(map[int32]*recordEntry)
-	var recordOrder []int32
-	for _, row := range rows {
-		if row.RecordID == newRecordID {
-			continue
+	previous, err := q.GetRecordForUpdate(ctx, database.GetRecordForUpdateParams{
+		GuildID:  input.GuildID,
+		RecordID: int32(input.RecordID),
+	})
+	if ei := database.ClassifyError(err); ei != nil {
+		if ei.Recoverable && ei.Code == "P0002" {
+			return nil, models.NewTectonicError(models.ERROR_RECORD_NOT_FOUND)
 		}
-		if _, ok := recordMap[row.RecordID]; !ok {
-			recordMap[row.RecordID] = &recordEntry{
-				RecordID: row.RecordID,
-				Value:    row.Value,
-				UserID:   row.UserID,
-			}
-			recordOrder = append(recordOrder, row.RecordID)
+		return nil, s.dbError(*ei)
+	}
+	approved := previous.Status == "approved"
+
+	previousBoss, err := q.GetBossInfo(ctx, database.GetBossInfoParams{
+		BossName: previous.BossName,
+		GuildID:  input.GuildID,
+	})
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+
+	settings, err := q.GetGuildRecordSettings(ctx, input.GuildID)
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+	policy := models.TiePolicy(settings.TiePolicy)
+
+	res := models.UpdateRecordResponse{PreviousBossName: previous.BossName}
+	if approved {
+		previousRecords, err := q.GetBossRecords(ctx, database.GetBossRecordsParams{
+			GuildID:  input.GuildID,
+			BossName: previous.BossName,
+			SeasonID: previous.SeasonID,
+		})
+		if ei := database.ClassifyError(err); ei != nil {
+			return nil, s.dbError(*ei)
+		}
+		if position := models.RecordPosition(previous.RecordID, previousRecords, previousBoss, policy); position > 0 {
+			res.PreviousPosition = &position
 		}
 	}
 
-	var eligible []recordEntry
-	if bossInfo.Solo {
-		bestByUser := make(map[string]*recordEntry)
-		for _, rid := range recordOrder {
-			entry := recordMap[rid]
-			existing, ok := bestByUser[entry.UserID]
-			if !ok || isBetter(entry.Value, existing.Value, bossInfo.HigherIsBetter) {
-				bestByUser[entry.UserID] = entry
+	bossInfo := previousBoss
+	params := database.UpdateRecordParams{
+		GuildID:  input.GuildID,
+		RecordID: previous.RecordID,
+	}
+	if input.Body.BossName != nil && *input.Body.BossName != previous.BossName {
+		bossInfo, err = q.GetBossInfo(ctx, database.GetBossInfoParams{
+			BossName: *input.Body.BossName,
+			GuildID:  input.GuildID,
+		})
+		if ei := database.ClassifyError(err); ei != nil {
+			if ei.Recoverable && ei.Code == "P0002" {
+				return nil, models.NewTectonicError(models.ERROR_GUILD_BOSS_NOT_FOUND)
 			}
+			return nil, s.dbError(*ei)
+		}
+		if bossInfo.Retired {
+			return nil, models.NewTectonicError(models.ERROR_BOSS_RETIRED)
+		}
+		if !bossInfo.Enabled {
+			return nil, models.NewTectonicError(models.ERROR_GUILD_BOSS_DISABLED)
+		}
+		params.BossName = pgtype.Text{String: bossInfo.Name, Valid: true}
+	}
+	if input.Body.Value != nil || input.Body.Time != nil {
+		value, err := recordValue(utils.DerefOr(input.Body.Value, 0), utils.DerefOr(input.Body.Time, ""), bossValueFormat(bossInfo))
+		if err != nil {
+			return nil, err
+		}
+		params.Value = pgtype.Int4{Int32: int32(value), Valid: true}
+	} else if params.BossName.Valid {
+		// The kept value has to fit the new boss's value type as well
+		if _, err := recordValue(int(previous.Value), "", bossValueFormat(bossInfo)); err != nil {
+			return nil, err
+		}
+	}
+	if input.Body.Date != nil {
+		params.Date = pgtype.Timestamp{Time: *input.Body.Date, Valid: true}
+	}
+	// Moving a record to another boss can leave its date before the release
+	if input.Body.Date != nil || params.BossName.Valid {
+		date := previous.Date.Time
+		if input.Body.Date != nil {
+			date = *input.Body.Date
 		}
-		for _, entry := range bestByUser {
-			eligible = append(eligible, *entry)
+		if err := checkRecordDate(date, bossInfo.ReleasedAt); err != nil {
+			return nil, err
 		}
-	} else {
-		for _, rid := range recordOrder {
-			eligible = append(eligible, *recordMap[rid])
+	}
+
+	record, err := q.UpdateRecord(ctx, params)
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+
+	if input.Body.UserIDs != nil {
+		err = q.DeleteTeam(ctx, database.DeleteTeamParams{
+			RecordID: record.RecordID,
+			GuildID:  input.GuildID,
+		})
+		if ei := database.ClassifyError(err); ei != nil {
+			return nil, s.dbError(*ei)
+		}
+		err = q.CreateTeam(ctx, database.CreateTeamParams{
+			RecordID: record.RecordID,
+			UserIds:  models.SnowflakesToStrings(input.Body.UserIDs),
+			GuildID:  input.GuildID,
+		})
+		if ei := database.ClassifyError(err); ei != nil {
+			return nil, s.dbError(*ei)
 		}
 	}
 
-	sort.Slice(eligible, func(i, j int) bool {
-		return isBetter(eligible[i].Value, eligible[j].Value, bossInfo.HigherIsBetter)
+	res.RecordResponse = models.RecordResponse{
+		BossName:     record.BossName,
+		Value:        int(record.Value),
+		DisplayValue: utils.FormatValue(bossValueFormat(bossInfo), int(record.Value)),
+		RecordID:     int(record.RecordID),
+		Status:       record.Status,
+	}
+
+	// Pending and rejected records aren't ranked, there's nothing to place
+	if approved {
+		allRecords, err := q.GetBossRecords(ctx, database.GetBossRecordsParams{
+			GuildID:  input.GuildID,
+			BossName: record.BossName,
+			SeasonID: record.SeasonID,
+		})
+		if ei := database.ClassifyError(err); ei != nil {
+			return nil, s.dbError(*ei)
+		}
+
+		placement := models.PlaceRecord(record.RecordID, allRecords, bossInfo, policy, 0)
+		if placement.Position > 0 {
+			res.Position = &placement.Position
+		}
+		res.OldValue = placement.OldValue
+		if res.OldValue > 0 {
+			res.DisplayOldValue = utils.FormatValue(bossValueFormat(bossInfo), res.OldValue)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	return &UpdateRecordOutput{Body: res}, nil
+}
+
+type GetRecordInput struct {
+	GuildID  string `path:"guild_id" doc:"Guild Snowflake ID"`
+	RecordID int    `path:"record_id" doc:"Record ID"`
+}
+type GetRecordOutput struct {
+	Body models.RecordDetail
+}
+
+func (s *Server) GetRecord(ctx context.Context, input *GetRecordInput) (*GetRecordOutput, error) {
+	rows, ei := database.WrapQuery(s.queries.GetRecord, ctx, database.GetRecordParams{
+		GuildID:  input.GuildID,
+		RecordID: int32(input.RecordID),
 	})
+	if ei != nil {
+		return nil, s.dbError(*ei)
+	}
+	if len(rows) == 0 {
+		return nil, models.NewTectonicError(models.ERROR_RECORD_NOT_FOUND)
+	}
+	formats, err := s.getValueFormats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	record := models.RecordDetailFromRows(rows)
+	record.DisplayValue = formats.format(record.ValueType, int(record.Value))
+	return &GetRecordOutput{Body: record}, nil
+}
+
+// Evidence
+
+type AddRecordEvidenceInput struct {
+	GuildID  string `path:"guild_id" doc:"Guild Snowflake ID"`
+	RecordID int    `path:"record_id" doc:"Record ID"`
+	Body     models.AddRecordEvidenceBody
+}
 
-	// Return the value that was at this position before
-	idx := position - 1
-	if idx < len(eligible) {
-		return int(eligible[idx].Value)
+func (s *Server) AddRecordEvidence(ctx context.Context, input *AddRecordEvidenceInput) (*GetRecordOutput, error) {
+	kinds, urls, submittedBy := models.EvidenceColumns(input.Body.Evidence)
+	added, ei := database.WrapQuery(s.queries.CreateRecordEvidence, ctx, database.CreateRecordEvidenceParams{
+		Kinds:       kinds,
+		Urls:        urls,
+		SubmittedBy: submittedBy,
+		GuildID:     input.GuildID,
+		RecordID:    int32(input.RecordID),
+	})
+	if ei != nil {
+		return nil, s.dbError(*ei)
 	}
-	return 0
+	if added == 0 {
+		return nil, models.NewTectonicError(models.ERROR_RECORD_NOT_FOUND)
+	}
+	return s.GetRecord(ctx, &GetRecordInput{GuildID: input.GuildID, RecordID: input.RecordID})
 }
 
-func isBetter(a, b int32, higherIsBetter bool) bool {
-	if higherIsBetter {
-		return a > b
+// Moderation
+
+type GetPendingRecordsInput struct {
+	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+}
+type GetPendingRecordsOutput struct {
+	Body models.PendingRecordsResponse
+}
+
+func (s *Server) GetPendingRecords(ctx context.Context, input *GetPendingRecordsInput) (*GetPendingRecordsOutput, error) {
+	settings, err := s.queries.GetGuildRecordSettings(ctx, input.GuildID)
+	if ei := database.ClassifyError(err); ei != nil {
+		if ei.Recoverable && ei.Code == "P0002" {
+			return nil, models.NewTectonicError(models.ERROR_GUILD_NOT_FOUND)
+		}
+		return nil, s.dbError(*ei)
+	}
+
+	rows, err := s.queries.GetPendingRecords(ctx, input.GuildID)
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+
+	formats, err := s.getValueFormats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := models.PendingRecordsResponse{
+		Records: models.PendingRecordsFromRows(rows),
+	}
+	for i, r := range res.Records {
+		res.Records[i].DisplayValue = formats.format(r.ValueType, int(r.Value))
 	}
-	return a < b
+	if settings.ModChannelID.Valid {
+		res.ModChannelID = &settings.ModChannelID.String
+	}
+	return &GetPendingRecordsOutput{Body: res}, nil
 }
 
-type RemoveRecordInput struct {
+type ApproveRecordInput struct {
+	GuildID  string `path:"guild_id" doc:"Guild Snowflake ID"`
+	RecordID int    `path:"record_id" doc:"Record ID"`
+	Body     models.ApproveRecordBody
+}
+type ApproveRecordOutput struct {
+	Body models.RecordResponse
+}
+
+func (s *Server) ApproveRecord(ctx context.Context, input *ApproveRecordInput) (*ApproveRecordOutput, error) {
+	tx, err := database.CreateTx(ctx)
+	if err != nil {
+		logging.Get().Error("Error creating transaction", "error", err)
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	defer tx.Rollback(ctx)
+
+	q := s.queries.WithTx(tx)
+
+	if _, err := s.lockRecordBoss(ctx, q, input.GuildID, int32(input.RecordID)); err != nil {
+		return nil, err
+	}
+
+	params := database.ReviewRecordParams{
+		Status:     "approved",
+		ReviewedBy: pgtype.Text{String: string(input.Body.ReviewedBy), Valid: true},
+		GuildID:    input.GuildID,
+		RecordID:   int32(input.RecordID),
+	}
+	if input.Body.Reason != nil {
+		params.ReviewReason = pgtype.Text{String: *input.Body.Reason, Valid: true}
+	}
+	record, err := s.reviewRecord(ctx, q, params)
+	if err != nil {
+		return nil, err
+	}
+
+	bossInfo, err := q.GetBossInfo(ctx, database.GetBossInfoParams{
+		BossName: record.BossName,
+		GuildID:  input.GuildID,
+	})
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+
+	settings, err := q.GetGuildRecordSettings(ctx, input.GuildID)
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+
+	// The record is now approved, so it takes part in the ranking
+	allRecords, err := q.GetBossRecords(ctx, database.GetBossRecordsParams{
+		GuildID:  input.GuildID,
+		BossName: record.BossName,
+		SeasonID: record.SeasonID,
+	})
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+
+	res := models.RecordResponse{
+		BossName:     record.BossName,
+		Value:        int(record.Value),
+		DisplayValue: utils.FormatValue(bossValueFormat(bossInfo), int(record.Value)),
+		RecordID:     int(record.RecordID),
+		Status:       "approved",
+	}
+	res.ApplyPlacement(models.PlaceRecord(record.RecordID, allRecords, bossInfo, models.TiePolicy(settings.TiePolicy), int(settings.PositionCount)))
+	if res.OldValue > 0 {
+		res.DisplayOldValue = utils.FormatValue(bossValueFormat(bossInfo), res.OldValue)
+	}
+
+	return &ApproveRecordOutput{Body: res}, nil
+}
+
+type RejectRecordInput struct {
 	GuildID  string `path:"guild_id" doc:"Guild Snowflake ID"`
 	RecordID int    `path:"record_id" doc:"Record ID"`
+	Body     models.RejectRecordBody
+}
+type RejectRecordOutput struct {
+	Body models.RecordResponse
+}
+
+func (s *Server) RejectRecord(ctx context.Context, input *RejectRecordInput) (*RejectRecordOutput, error) {
+	tx, err := database.CreateTx(ctx)
+	if err != nil {
+		logging.Get().Error("Error creating transaction", "error", err)
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	defer tx.Rollback(ctx)
+
+	q := s.queries.WithTx(tx)
+
+	record, err := s.reviewRecord(ctx, q, database.ReviewRecordParams{
+		Status:       "rejected",
+		ReviewedBy:   pgtype.Text{String: string(input.Body.ReviewedBy), Valid: true},
+		ReviewReason: pgtype.Text{String: input.Body.Reason, Valid: true},
+		GuildID:      input.GuildID,
+		RecordID:     int32(input.RecordID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+
+	formats, err := s.getValueFormats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RejectRecordOutput{Body: models.RecordResponse{
+		BossName:     record.BossName,
+		Value:        int(record.Value),
+		DisplayValue: formats.format(record.ValueType, int(record.Value)),
+		RecordID:     int(record.RecordID),
+		Status:       "rejected",
+	}}, nil
+}
+
+// reviewRecord moves a pending record to its reviewed status, locking it first
+// so concurrent moderators can't review the same submission twice.
+func (s *Server) reviewRecord(ctx context.Context, q *database.Queries, params database.ReviewRecordParams) (database.ReviewRecordRow, error) {
+	status, err := q.GetRecordStatus(ctx, database.GetRecordStatusParams{
+		GuildID:  params.GuildID,
+		RecordID: params.RecordID,
+	})
+	if ei := database.ClassifyError(err); ei != nil {
+		if ei.Recoverable && ei.Code == "P0002" {
+			return database.ReviewRecordRow{}, models.NewTectonicError(models.ERROR_RECORD_NOT_FOUND)
+		}
+		return database.ReviewRecordRow{}, s.dbError(*ei)
+	}
+	if status != "pending" {
+		return database.ReviewRecordRow{}, models.NewTectonicError(models.ERROR_RECORD_NOT_PENDING)
+	}
+
+	record, err := q.ReviewRecord(ctx, params)
+	if ei := database.ClassifyError(err); ei != nil {
+		return database.ReviewRecordRow{}, s.dbError(*ei)
+	}
+	return record, nil
+}
+
+type RemoveRecordInput struct {
+	GuildID   string `path:"guild_id" doc:"Guild Snowflake ID"`
+	RecordID  int    `path:"record_id" doc:"Record ID"`
+	DeletedBy string `query:"deleted_by" pattern:"^[1-9][0-9]{16,18}$" doc:"Snowflake ID of the user deleting the record"`
 }
 
 func (s *Server) RemoveRecord(ctx context.Context, input *RemoveRecordInput) (*struct{}, error) {
+	tx, err := database.CreateTx(ctx)
+	if err != nil {
+		logging.Get().Error("Error creating transaction", "error", err)
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	defer tx.Rollback(ctx)
+
+	q := s.queries.WithTx(tx)
+
+	if _, err := s.lockRecordBoss(ctx, q, input.GuildID, int32(input.RecordID)); err != nil {
+		return nil, err
+	}
+
 	params := database.DeleteRecordParams{
-		GuildID:  input.GuildID,
-		RecordID: int32(input.RecordID),
+		DeletedBy: pgtype.Text{String: input.DeletedBy, Valid: input.DeletedBy != ""},
+		GuildID:   input.GuildID,
+		RecordID:  int32(input.RecordID),
 	}
-	deleted, err := s.queries.DeleteRecord(ctx, params)
+	deleted, err := q.DeleteRecord(ctx, params)
 	if ei := database.ClassifyError(err); ei != nil {
 		return nil, s.dbError(*ei)
 	}
 	if deleted == 0 {
 		return nil, models.NewTectonicError(models.ERROR_RECORD_NOT_FOUND)
 	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
 	return nil, nil
 }
 
 type ClearBossRecordsInput struct {
-	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
-	Boss    string `path:"boss" doc:"Boss name"`
+	GuildID   string `path:"guild_id" doc:"Guild Snowflake ID"`
+	Boss      string `path:"boss" doc:"Boss name"`
+	DeletedBy string `query:"deleted_by" pattern:"^[1-9][0-9]{16,18}$" doc:"Snowflake ID of the user deleting the records"`
 }
 
 func (s *Server) ClearBossRecords(ctx context.Context, input *ClearBossRecordsInput) (*struct{}, error) {
-	removed, err := s.queries.DeleteBossRecords(ctx, database.DeleteBossRecordsParams{
-		GuildID:  input.GuildID,
-		BossName: input.Boss,
+	tx, err := database.CreateTx(ctx)
+	if err != nil {
+		logging.Get().Error("Error creating transaction", "error", err)
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	defer tx.Rollback(ctx)
+
+	q := s.queries.WithTx(tx)
+
+	if err := s.lockBosses(ctx, q, input.GuildID, input.Boss); err != nil {
+		return nil, err
+	}
+
+	removed, err := q.DeleteBossRecords(ctx, database.DeleteBossRecordsParams{
+		DeletedBy: pgtype.Text{String: input.DeletedBy, Valid: input.DeletedBy != ""},
+		GuildID:   input.GuildID,
+		BossName:  input.Boss,
 	})
 	if ei := database.ClassifyError(err); ei != nil {
 		return nil, s.dbError(*ei)
@@ -359,18 +912,37 @@ func (s *Server) ClearBossRecords(ctx context.Context, input *ClearBossRecordsIn
 	if removed == 0 {
 		return nil, models.NewTectonicError(models.ERROR_RECORD_NOT_FOUND)
 	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
 	return nil, nil
 }
 
 type RevertTopRecordInput struct {
-	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
-	Boss    string `path:"boss" doc:"Boss name"`
+	GuildID   string `path:"guild_id" doc:"Guild Snowflake ID"`
+	Boss      string `path:"boss" doc:"Boss name"`
+	DeletedBy string `query:"deleted_by" pattern:"^[1-9][0-9]{16,18}$" doc:"Snowflake ID of the user deleting the records"`
 }
 
 func (s *Server) RevertTopRecord(ctx context.Context, input *RevertTopRecordInput) (*struct{}, error) {
-	removed, err := s.queries.DeleteTopRecord(ctx, database.DeleteTopRecordParams{
-		GuildID:  input.GuildID,
-		BossName: input.Boss,
+	tx, err := database.CreateTx(ctx)
+	if err != nil {
+		logging.Get().Error("Error creating transaction", "error", err)
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	defer tx.Rollback(ctx)
+
+	q := s.queries.WithTx(tx)
+
+	if err := s.lockBosses(ctx, q, input.GuildID, input.Boss); err != nil {
+		return nil, err
+	}
+
+	removed, err := q.DeleteTopRecord(ctx, database.DeleteTopRecordParams{
+		DeletedBy: pgtype.Text{String: input.DeletedBy, Valid: input.DeletedBy != ""},
+		GuildID:   input.GuildID,
+		BossName:  input.Boss,
 	})
 	if ei := database.ClassifyError(err); ei != nil {
 		return nil, s.dbError(*ei)
@@ -378,5 +950,9 @@ func (s *Server) RevertTopRecord(ctx context.Context, input *RevertTopRecordInpu
 	if removed == 0 {
 		return nil, models.NewTectonicError(models.ERROR_RECORD_NOT_FOUND)
 	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
 	return nil, nil
 }
@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"tectonic-api/database"
+	"tectonic-api/logging"
+	"tectonic-api/models"
+	"tectonic-api/utils"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const maxImportRows = 1000
+
+type ImportRecordsInput struct {
+	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+	DryRun  bool   `query:"dry_run" default:"false" doc:"Validate and report without inserting anything"`
+	Body    models.ImportRecordsBody
+}
+type ImportRecordsOutput struct {
+	Body models.ImportRecordsResponse
+}
+
+// importedRecord is a validated row waiting to be inserted
+type importedRecord struct {
+	result   *models.ImportRecordResult
+	bossName string
+	value    int
+	date     time.Time
+	userIDs  []string
+}
+
+// ImportRecords validates every row on its own and inserts the valid ones in
+// a single transaction, rows that fail are reported back instead. Guilds with
+// a mod channel get the imported records in their review queue.
+func (s *Server) ImportRecords(ctx context.Context, input *ImportRecordsInput) (*ImportRecordsOutput, error) {
+	rows := input.Body.Rows
+	if input.Body.CSV != "" {
+		parsed, err := utils.ParseRecordCSV(input.Body.CSV)
+		if err != nil {
+			return nil, importCSVError(err.Error())
+		}
+		if len(parsed) > maxImportRows {
+			return nil, importCSVError(fmt.Sprintf("at most %d rows can be imported at once", maxImportRows))
+		}
+		rows = parsed
+	}
+
+	bossRows, err := s.queries.GetGuildAvailableBosses(ctx, input.GuildID)
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+	bosses := make(map[string]database.Boss, len(bossRows))
+	for _, b := range bossRows {
+		bosses[b.Name] = b
+	}
+
+	formats, err := s.getValueFormats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.queries.GetGuildRecordSettings(ctx, input.GuildID)
+	if ei := database.ClassifyError(err); ei != nil {
+		if ei.Recoverable && ei.Code == "P0002" {
+			return nil, models.NewTectonicError(models.ERROR_GUILD_NOT_FOUND)
+		}
+		return nil, s.dbError(*ei)
+	}
+
+	var participants, bossNames []string
+	for _, row := range rows {
+		for _, p := range row.Participants {
+			participants = append(participants, strings.ToLower(strings.TrimSpace(p)))
+		}
+		if _, ok := bosses[row.Boss]; ok {
+			bossNames = append(bossNames, row.Boss)
+		}
+	}
+
+	resolvedRows, ei := database.WrapQuery(s.queries.ResolveGuildParticipants, ctx, database.ResolveGuildParticipantsParams{
+		GuildID:      input.GuildID,
+		Participants: participants,
+	})
+	if ei != nil {
+		return nil, s.dbError(*ei)
+	}
+	resolved := make(map[string]string, len(resolvedRows))
+	for _, r := range resolvedRows {
+		resolved[r.Participant] = r.UserID
+	}
+
+	tx, err := database.CreateTx(ctx)
+	if err != nil {
+		logging.Get().Error("Error creating transaction", "error", err)
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	defer tx.Rollback(ctx)
+
+	q := s.queries.WithTx(tx)
+
+	// Duplicates are checked under the boss locks so records created in the
+	// meantime can't slip in, a dry run holds them too and rolls back
+	if err := s.lockBosses(ctx, q, input.GuildID, bossNames...); err != nil {
+		return nil, err
+	}
+
+	existingRows, ei := database.WrapQuery(q.GetGuildRecordTeams, ctx, database.GetGuildRecordTeamsParams{
+		GuildID:   input.GuildID,
+		BossNames: bossNames,
+	})
+	if ei != nil {
+		return nil, s.dbError(*ei)
+	}
+	existing := make(map[string]bool, len(existingRows))
+	for _, r := range existingRows {
+		existing[importKey(r.BossName, int(r.Value), r.UserIds)] = true
+	}
+
+	res := models.ImportRecordsResponse{
+		DryRun:       input.DryRun,
+		RecordStatus: "approved",
+		Rows:         make([]models.ImportRecordResult, len(rows)),
+	}
+	if settings.ModChannelID.Valid {
+		res.RecordStatus = "pending"
+	}
+	seen := make(map[string]bool)
+	var valid []importedRecord
+
+	now := time.Now()
+	for i, row := range rows {
+		result := &res.Rows[i]
+		result.Row = i + 1
+		result.BossName = row.Boss
+
+		record, reason := validateImportRow(row, bosses, formats, resolved, now)
+		if reason != "" {
+			result.Status = "rejected"
+			result.Reason = reason
+			res.Rejected++
+			continue
+		}
+		result.Value = record.value
+		result.UserIDs = record.userIDs
+
+		key := importKey(record.bossName, record.value, record.userIDs)
+		if existing[key] || seen[key] {
+			result.Status = "duplicate"
+			if existing[key] {
+				result.Reason = "record already exists"
+			} else {
+				result.Reason = "same record as an earlier row"
+			}
+			res.Duplicates++
+			continue
+		}
+		seen[key] = true
+
+		result.Status = "created"
+		record.result = result
+		valid = append(valid, record)
+		res.Created++
+	}
+
+	if input.DryRun || len(valid) == 0 {
+		return &ImportRecordsOutput{Body: res}, nil
+	}
+
+	for _, record := range valid {
+		created, err := q.CreateRecord(ctx, database.CreateRecordParams{
+			Value:    int32(record.value),
+			BossName: record.bossName,
+			Date:     pgtype.Timestamp{Time: record.date, Valid: true},
+			GuildID:  input.GuildID,
+			Status:   res.RecordStatus,
+		})
+		if ei := database.ClassifyError(err); ei != nil {
+			return nil, s.dbError(*ei)
+		}
+
+		err = q.CreateTeam(ctx, database.CreateTeamParams{
+			RecordID: created.RecordID,
+			UserIds:  record.userIDs,
+			GuildID:  input.GuildID,
+		})
+		if ei := database.ClassifyError(err); ei != nil {
+			return nil, s.dbError(*ei)
+		}
+		record.result.RecordID = &created.RecordID
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	return &ImportRecordsOutput{Body: res}, nil
+}
+
+// validateImportRow returns the reason a row can't be imported, or an empty
+// reason when the row is valid
+func validateImportRow(row models.ImportRecordRow, bosses map[string]database.Boss, formats valueFormats, resolved map[string]string, now time.Time) (importedRecord, string) {
+	boss, ok := bosses[row.Boss]
+	if !ok {
+		return importedRecord{}, fmt.Sprintf("unknown boss %q", row.Boss)
+	}
+
+	format := formats[boss.ValueType]
+	var value int
+	var err error
+	if strings.ContainsAny(row.Value, ":.") {
+		value, err = format.ParseTime(row.Value)
+	} else {
+		value, err = strconv.Atoi(row.Value)
+	}
+	if err != nil {
+		return importedRecord{}, fmt.Sprintf("invalid value %q: %v", row.Value, err)
+	}
+	if value <= 0 {
+		return importedRecord{}, "value must be greater than zero"
+	}
+	// Validate also rejects values too large to store, rows must never wrap
+	if err := format.Validate(value); err != nil {
+		return importedRecord{}, err.Error()
+	}
+
+	date := now
+	if row.Date != "" {
+		date, err = time.Parse(time.RFC3339, row.Date)
+		if err != nil {
+			date, err = time.Parse(time.DateOnly, row.Date)
+		}
+		if err != nil {
+			return importedRecord{}, fmt.Sprintf("invalid date %q, expected RFC 3339 or YYYY-MM-DD", row.Date)
+		}
+	}
+	if reason := recordDateError(date, boss.ReleasedAt); reason != "" {
+		return importedRecord{}, reason
+	}
+
+	if len(row.Participants) == 0 || len(row.Participants) > 8 {
+		return importedRecord{}, "a record needs between 1 and 8 participants"
+	}
+	userIDs := make([]string, 0, len(row.Participants))
+	for _, p := range row.Participants {
+		userID, ok := resolved[strings.ToLower(strings.TrimSpace(p))]
+		if !ok {
+			return importedRecord{}, fmt.Sprintf("participant %q is not a member of this guild", p)
+		}
+		if !slices.Contains(userIDs, userID) {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	slices.Sort(userIDs)
+
+	return importedRecord{
+		bossName: boss.Name,
+		value:    value,
+		date:     date,
+		userIDs:  userIDs,
+	}, ""
+}
+
+// importKey identifies a record by boss, value and team, userIDs must be sorted
+func importKey(bossName string, value int, userIDs []string) string {
+	return fmt.Sprintf("%s|%d|%s", bossName, value, strings.Join(userIDs, ","))
+}
+
+func importCSVError(message string) error {
+	return models.NewTectonicErrorWithDetails(models.ERROR_VALIDATION_FAILED, []*huma.ErrorDetail{{
+		Location: "body.csv",
+		Message:  message,
+	}})
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+
+	"tectonic-api/database"
+	"tectonic-api/logging"
+	"tectonic-api/models"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// getSeason resolves the season a read is scoped to, seasonID 0 is the
+// guild's current season
+func (s *Server) getSeason(ctx context.Context, guildID string, seasonID int32) (database.GetSeasonRow, error) {
+	params := database.GetSeasonParams{GuildID: guildID}
+	if seasonID != 0 {
+		params.SeasonID = pgtype.Int4{Int32: seasonID, Valid: true}
+	}
+
+	season, err := s.queries.GetSeason(ctx, params)
+	if ei := database.ClassifyError(err); ei != nil {
+		if ei.Recoverable && ei.Code == "P0002" {
+			return database.GetSeasonRow{}, models.NewTectonicError(models.ERROR_SEASON_NOT_FOUND)
+		}
+		return database.GetSeasonRow{}, s.dbError(*ei)
+	}
+	return season, nil
+}
+
+type GetSeasonsInput struct {
+	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+}
+type GetSeasonsOutput struct {
+	Body []models.Season
+}
+
+func (s *Server) GetSeasons(ctx context.Context, input *GetSeasonsInput) (*GetSeasonsOutput, error) {
+	rows, err := s.queries.GetGuildSeasons(ctx, input.GuildID)
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+	if len(rows) == 0 {
+		return nil, models.NewTectonicError(models.ERROR_GUILD_NOT_FOUND)
+	}
+	return &GetSeasonsOutput{Body: models.SeasonsFromRows(rows)}, nil
+}
+
+type StartSeasonInput struct {
+	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
+	Body    models.StartSeasonBody
+}
+type StartSeasonOutput struct {
+	Body models.StartSeasonResponse
+}
+
+// StartSeason closes the current season and opens the next one, records of
+// the closed season stay readable through the season parameter
+func (s *Server) StartSeason(ctx context.Context, input *StartSeasonInput) (*StartSeasonOutput, error) {
+	tx, err := database.CreateTx(ctx)
+	if err != nil {
+		logging.Get().Error("Error creating transaction", "error", err)
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	defer tx.Rollback(ctx)
+
+	q := s.queries.WithTx(tx)
+
+	// A concurrent start waits here and then closes the season this one opens
+	_, err = q.LockGuildSeasons(ctx, input.GuildID)
+	if ei := database.ClassifyError(err); ei != nil {
+		if ei.Recoverable && ei.Code == "P0002" {
+			return nil, models.NewTectonicError(models.ERROR_GUILD_NOT_FOUND)
+		}
+		return nil, s.dbError(*ei)
+	}
+
+	closed, err := q.CloseSeason(ctx, input.GuildID)
+	if ei := database.ClassifyError(err); ei != nil {
+		if ei.Recoverable && ei.Code == "P0002" {
+			return nil, models.NewTectonicError(models.ERROR_SEASON_NOT_FOUND)
+		}
+		return nil, s.dbError(*ei)
+	}
+
+	params := database.CreateSeasonParams{GuildID: input.GuildID}
+	if input.Body.Name != nil {
+		params.Name = pgtype.Text{String: *input.Body.Name, Valid: true}
+	}
+	opened, err := q.CreateSeason(ctx, params)
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
+	}
+	return &StartSeasonOutput{Body: models.StartSeasonResponseFromRows(closed, opened)}, nil
+}
@@ -9,104 +9,158 @@ import (
 	"tectonic-api/models"
 )
 
-// Unchanged private helper
-func (s *Server) getDetailedUsers(ctx context.Context, userIDs []string, guildID string) ([]models.DetailedUser, *database.ErrorInfo) {
+// getDetailedUsers loads every user in a fixed number of queries no matter
+// how many users are requested, users are returned in the order of userIDs.
+// Queries for fields that weren't requested are skipped entirely. Records and
+// events are the ones of the season seasonID.
+func (s *Server) getDetailedUsers(ctx context.Context, userIDs []string, guildID string, seasonID int32, fields models.UserFields) ([]models.DetailedUser, *database.ErrorInfo) {
 	if len(userIDs) == 0 {
 		return []models.DetailedUser{}, nil
 	}
 
-	detailedUsers := make([]models.DetailedUser, 0, len(userIDs))
+	userRows, err := database.WrapQuery(s.queries.GetUsersById, ctx, database.GetUsersByIdParams{
+		GuildID: guildID,
+		UserIds: userIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(userRows) == 0 {
+		return []models.DetailedUser{}, nil
+	}
 
-	for _, userID := range userIDs {
-		userRows, err := database.WrapQuery(s.queries.GetUsersById, ctx, database.GetUsersByIdParams{
-			GuildID: guildID,
-			UserIds: []string{userID},
+	rsns := make(map[string][]database.GetUsersRsnsRow)
+	if fields.RSNs {
+		rows, err := database.WrapQuery(s.queries.GetUsersRsns, ctx, database.GetUsersRsnsParams{
+			UserIds: userIDs, GuildID: guildID,
 		})
 		if err != nil {
 			return nil, err
 		}
-		if len(userRows) != 1 {
-			continue
+		for _, row := range rows {
+			rsns[row.UserID] = append(rsns[row.UserID], row)
 		}
+	}
 
-		user := userRows[0]
-
-		rsnsRows, err := database.WrapQuery(s.queries.GetUserRsns, ctx, database.GetUserRsnsParams{
-			UserID: userID, GuildID: guildID,
+	records := make(map[string][]database.GetUserRecordsRow)
+	var formats valueFormats
+	if fields.Records {
+		rows, err := database.WrapQuery(s.queries.GetUsersRecords, ctx, database.GetUsersRecordsParams{
+			UserIds: userIDs, GuildID: guildID, SeasonID: seasonID,
 		})
 		if err != nil {
 			return nil, err
 		}
+		for _, row := range rows {
+			records[row.UserID] = append(records[row.UserID], database.GetUserRecordsRow(row))
+		}
 
-		recordsRows, err := database.WrapQuery(s.queries.GetUserRecords, ctx, database.GetUserRecordsParams{
-			UserID: userID, GuildID: guildID,
-		})
-		if err != nil {
-			return nil, err
+		valueTypes, vtErr := s.queries.GetValueTypes(ctx)
+		if ei := database.ClassifyError(vtErr); ei != nil {
+			return nil, ei
 		}
+		formats = valueFormatsFromRows(valueTypes)
+	}
 
-		achievementsRows, err := database.WrapQuery(s.queries.GetUserAchievements, ctx, userID)
+	achievements := make(map[string][]database.GetUsersAchievementsRow)
+	if fields.Achievements {
+		rows, err := database.WrapQuery(s.queries.GetUsersAchievements, ctx, userIDs)
 		if err != nil {
 			return nil, err
 		}
+		for _, row := range rows {
+			achievements[row.UserID] = append(achievements[row.UserID], row)
+		}
+	}
 
-		eventsRows, err := database.WrapQuery(s.queries.GetUserEvents, ctx, database.GetUserEventsParams{
-			UserID: userID, GuildID: guildID,
+	events := make(map[string][]database.GetUsersEventsRow)
+	if fields.Events {
+		rows, err := database.WrapQuery(s.queries.GetUsersEvents, ctx, database.GetUsersEventsParams{
+			UserIds: userIDs, GuildID: guildID, SeasonID: seasonID,
 		})
 		if err != nil {
 			return nil, err
 		}
+		for _, row := range rows {
+			events[row.UserID] = append(events[row.UserID], row)
+		}
+	}
 
-		caRows, err := database.WrapQuery(s.queries.GetUserCombatAchievements, ctx, database.GetUserCombatAchievementsParams{
-			UserID: userID, GuildID: guildID,
+	cas := make(map[string][]database.GetUsersCombatAchievementsRow)
+	if fields.CombatAchievements {
+		rows, err := database.WrapQuery(s.queries.GetUsersCombatAchievements, ctx, database.GetUsersCombatAchievementsParams{
+			UserIds: userIDs, GuildID: guildID,
 		})
 		if err != nil {
 			return nil, err
 		}
+		for _, row := range rows {
+			cas[row.UserID] = append(cas[row.UserID], row)
+		}
+	}
 
-		// Get user rank (leaderboard position)
-		var userRank int64
-		rank, rankErr := database.WrapQuery(s.queries.GetUserRank, ctx, database.GetUserRankParams{
+	// Leaderboard position
+	userRanks := make(map[string]int64)
+	if fields.Rank {
+		rows, err := database.WrapQuery(s.queries.GetUsersRank, ctx, database.GetUsersRankParams{
 			GuildID: guildID,
-			UserID:  userID,
+			UserIds: userIDs,
 		})
-		if rankErr == nil {
-			userRank = rank
+		if err != nil {
+			return nil, err
 		}
+		for _, row := range rows {
+			userRanks[row.UserID] = row.UserRank
+		}
+	}
 
-		// Get user tier (based on points)
-		var userTier *models.UserTier
-		tier, tierErr := database.WrapQuery(s.queries.GetUserTier, ctx, database.GetUserTierParams{
-			GuildID: guildID,
-			Points:  user.Points,
-		})
-		if tierErr == nil {
-			t := models.UserTier{
-				Name:         tier.Name,
-				MinPoints:    tier.MinPoints,
-				DisplayOrder: tier.DisplayOrder,
-			}
-			if tier.Icon.Valid {
-				t.Icon = &tier.Icon.String
-			}
-			if tier.RoleID.Valid {
-				t.RoleID = &tier.RoleID.String
-			}
-			userTier = &t
+	// Tiers are resolved from the guild's ranks based on points
+	var ranks []database.GetGuildRanksRow
+	if fields.Tier {
+		ranks, err = database.WrapQuery(s.queries.GetGuildRanks, ctx, guildID)
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		detailedUsers = append(detailedUsers, models.DetailedUser{
-			UserId:             user.UserID,
-			GuildId:            user.GuildID,
-			Points:             int(user.Points),
-			Rank:               userRank,
-			Tier:               userTier,
-			RSNs:               models.UserRsnsFromRows(rsnsRows),
-			Records:            models.UserRecordsFromRows(recordsRows),
-			Events:             models.UserEventFromRows(eventsRows),
-			Achievements:       models.UserAchievementsFromRows(achievementsRows),
-			CombatAchievements: models.UserCombatAchievementsFromRows(caRows),
-		})
+	users := make(map[string]database.GetUsersByIdRow, len(userRows))
+	for _, row := range userRows {
+		users[row.UserID] = row
+	}
+
+	detailedUsers := make([]models.DetailedUser, 0, len(userRows))
+	for _, userID := range userIDs {
+		user, ok := users[userID]
+		if !ok {
+			continue
+		}
+
+		u := models.DetailedUser{
+			UserId:  user.UserID,
+			GuildId: user.GuildID,
+			Points:  int(user.Points),
+			Rank:    userRanks[userID],
+		}
+		if fields.Tier {
+			u.Tier = models.TierForPoints(ranks, user.Points)
+		}
+		if fields.RSNs {
+			u.RSNs = models.UserRsnsFromRows(rsns[userID])
+		}
+		if fields.Records {
+			u.Records = models.UserRecordsFromRows(records[userID])
+			formatUserRecords(formats, u.Records)
+		}
+		if fields.Events {
+			u.Events = models.UserEventFromRows(events[userID])
+		}
+		if fields.Achievements {
+			u.Achievements = models.UserAchievementsFromRows(achievements[userID])
+		}
+		if fields.CombatAchievements {
+			u.CombatAchievements = models.UserCombatAchievementsFromRows(cas[userID])
+		}
+		detailedUsers = append(detailedUsers, u)
 	}
 
 	return detailedUsers, nil
@@ -115,15 +169,22 @@ func (s *Server) getDetailedUsers(ctx context.Context, userIDs []string, guildID
 // Handlers
 
 type GetUsersByIDInput struct {
-	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
-	UserIDs string `path:"user_ids" doc:"Comma-separated User Snowflake IDs"`
+	GuildID string   `path:"guild_id" doc:"Guild Snowflake ID"`
+	UserIDs string   `path:"user_ids" doc:"Comma-separated User Snowflake IDs"`
+	Include []string `query:"include" enum:"rsns,records,events,achievements,combat_achievements,rank,tier" doc:"Fields to include, every field is included when empty"`
+	Season  int32    `query:"season" minimum:"0" doc:"Season ID for records and events, defaults to the current season"`
 }
 type GetUsersByIDOutput struct {
 	Body []models.DetailedUser
 }
 
 func (s *Server) GetUsersById(ctx context.Context, input *GetUsersByIDInput) (*GetUsersByIDOutput, error) {
-	users, ei := s.getDetailedUsers(ctx, strings.Split(input.UserIDs, ","), input.GuildID)
+	season, err := s.getSeason(ctx, input.GuildID, input.Season)
+	if err != nil {
+		return nil, err
+	}
+
+	users, ei := s.getDetailedUsers(ctx, strings.Split(input.UserIDs, ","), input.GuildID, season.SeasonID, models.ParseUserFields(input.Include))
 	if ei != nil {
 		return nil, s.dbError(*ei)
 	}
@@ -131,8 +192,10 @@ func (s *Server) GetUsersById(ctx context.Context, input *GetUsersByIDInput) (*G
 }
 
 type GetUsersByRsnInput struct {
-	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
-	RSNs    string `path:"rsns" doc:"Comma-separated RuneScape Names"`
+	GuildID string   `path:"guild_id" doc:"Guild Snowflake ID"`
+	RSNs    string   `path:"rsns" doc:"Comma-separated RuneScape Names"`
+	Include []string `query:"include" enum:"rsns,records,events,achievements,combat_achievements,rank,tier" doc:"Fields to include, every field is included when empty"`
+	Season  int32    `query:"season" minimum:"0" doc:"Season ID for records and events, defaults to the current season"`
 }
 type GetUsersByRsnOutput struct {
 	Body []models.DetailedUser
@@ -147,7 +210,12 @@ func (s *Server) GetUsersByRsn(ctx context.Context, input *GetUsersByRsnInput) (
 		return nil, s.dbError(*ei)
 	}
 
-	users, ei := s.getDetailedUsers(ctx, userIDs, input.GuildID)
+	season, err := s.getSeason(ctx, input.GuildID, input.Season)
+	if err != nil {
+		return nil, err
+	}
+
+	users, ei := s.getDetailedUsers(ctx, userIDs, input.GuildID, season.SeasonID, models.ParseUserFields(input.Include))
 	if ei != nil {
 		return nil, s.dbError(*ei)
 	}
@@ -155,8 +223,10 @@ func (s *Server) GetUsersByRsn(ctx context.Context, input *GetUsersByRsnInput) (
 }
 
 type GetUsersByWomInput struct {
-	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
-	WomIDs  string `path:"wom_ids" doc:"Comma-separated WOM IDs"`
+	GuildID string   `path:"guild_id" doc:"Guild Snowflake ID"`
+	WomIDs  string   `path:"wom_ids" doc:"Comma-separated WOM IDs"`
+	Include []string `query:"include" enum:"rsns,records,events,achievements,combat_achievements,rank,tier" doc:"Fields to include, every field is included when empty"`
+	Season  int32    `query:"season" minimum:"0" doc:"Season ID for records and events, defaults to the current season"`
 }
 type GetUsersByWomOutput struct {
 	Body []models.DetailedUser
@@ -171,7 +241,12 @@ func (s *Server) GetUsersByWom(ctx context.Context, input *GetUsersByWomInput) (
 		return nil, s.dbError(*ei)
 	}
 
-	users, ei := s.getDetailedUsers(ctx, userIDs, input.GuildID)
+	season, err := s.getSeason(ctx, input.GuildID, input.Season)
+	if err != nil {
+		return nil, err
+	}
+
+	users, ei := s.getDetailedUsers(ctx, userIDs, input.GuildID, season.SeasonID, models.ParseUserFields(input.Include))
 	if ei != nil {
 		return nil, s.dbError(*ei)
 	}
@@ -197,15 +272,22 @@ func (s *Server) GetUserAchievements(ctx context.Context, input *GetUserAchievem
 type GetUserEventsInput struct {
 	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
 	UserID  string `path:"user_id" doc:"User Snowflake ID"`
+	Season  int32  `query:"season" minimum:"0" doc:"Season ID, defaults to the current season"`
 }
 type GetUserEventsOutput struct {
 	Body []database.GetUserEventsRow
 }
 
 func (s *Server) GetUserEvents(ctx context.Context, input *GetUserEventsInput) (*GetUserEventsOutput, error) {
+	season, err := s.getSeason(ctx, input.GuildID, input.Season)
+	if err != nil {
+		return nil, err
+	}
+
 	events, ei := database.WrapQuery(s.queries.GetUserEvents, ctx, database.GetUserEventsParams{
-		UserID:  input.UserID,
-		GuildID: input.GuildID,
+		UserID:   input.UserID,
+		GuildID:  input.GuildID,
+		SeasonID: season.SeasonID,
 	})
 	if ei != nil {
 		return nil, s.dbError(*ei)
@@ -216,20 +298,34 @@ func (s *Server) GetUserEvents(ctx context.Context, input *GetUserEventsInput) (
 type GetUserRecordsInput struct {
 	GuildID string `path:"guild_id" doc:"Guild Snowflake ID"`
 	UserID  string `path:"user_id" doc:"User Snowflake ID"`
+	Season  int32  `query:"season" minimum:"0" doc:"Season ID, defaults to the current season"`
 }
 type GetUserRecordsOutput struct {
 	Body []models.UserRecord
 }
 
 func (s *Server) GetUserRecords(ctx context.Context, input *GetUserRecordsInput) (*GetUserRecordsOutput, error) {
+	season, err := s.getSeason(ctx, input.GuildID, input.Season)
+	if err != nil {
+		return nil, err
+	}
+
 	rows, ei := database.WrapQuery(s.queries.GetUserRecords, ctx, database.GetUserRecordsParams{
-		UserID:  input.UserID,
-		GuildID: input.GuildID,
+		UserID:   input.UserID,
+		GuildID:  input.GuildID,
+		SeasonID: season.SeasonID,
 	})
 	if ei != nil {
 		return nil, s.dbError(*ei)
 	}
-	return &GetUserRecordsOutput{Body: models.UserRecordsFromRows(rows)}, nil
+	formats, err := s.getValueFormats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userRecords := models.UserRecordsFromRows(rows)
+	formatUserRecords(formats, userRecords)
+	return &GetUserRecordsOutput{Body: userRecords}, nil
 }
 
 type CreateUserInput struct {
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
@@ -8,6 +9,8 @@ import (
 	"tectonic-api/logging"
 	"tectonic-api/models"
 	"tectonic-api/utils"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 func (s *Server) getConstraintError(ei database.ErrorInfo) models.APIV1Error {
@@ -47,6 +50,12 @@ func (s *Server) getConstraintError(ei database.ErrorInfo) models.APIV1Error {
 			return models.ERROR_USER_ACHIEVEMENT_NOT_FOUND
 		case "users":
 			return models.ERROR_USER_NOT_FOUND
+		case "api_keys":
+			return models.ERROR_API_KEY_NOT_FOUND
+		case "point_transactions":
+			return models.ERROR_POINT_TRANSACTION_NOT_FOUND
+		case "value_types":
+			return models.ERROR_VALUE_TYPE_NOT_FOUND
 		}
 	case "23505":
 		c := s.constraintsMap[ei.Err.ConstraintName]
@@ -83,6 +92,12 @@ func (s *Server) getConstraintError(ei database.ErrorInfo) models.APIV1Error {
 			return models.ERROR_USER_ACHIEVEMENT_EXISTS
 		case "users":
 			return models.ERROR_USER_EXISTS
+		case "api_keys":
+			return models.ERROR_API_KEY_EXISTS
+		case "point_transactions":
+			return models.ERROR_POINT_TRANSACTION_REVOKED
+		case "value_types":
+			return models.ERROR_VALUE_TYPE_EXISTS
 		}
 	}
 
@@ -115,3 +130,64 @@ func (s *Server) womError(err error) *models.TectonicError {
 	}
 	return models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
 }
+
+// Display values are filled in by handlers since formatting lives in utils,
+// which already depends on models
+
+// valueFormats maps value type names to their display rules
+type valueFormats map[string]utils.ValueFormat
+
+func (f valueFormats) format(valueType string, value int) string {
+	return utils.FormatValue(f[valueType], value)
+}
+
+// getValueFormats loads the display rules of every value type, they can
+// change through the catalog API so they're read per request
+func (s *Server) getValueFormats(ctx context.Context) (valueFormats, error) {
+	rows, err := s.queries.GetValueTypes(ctx)
+	if ei := database.ClassifyError(err); ei != nil {
+		return nil, s.dbError(*ei)
+	}
+	return valueFormatsFromRows(rows), nil
+}
+
+func valueFormatsFromRows(rows []database.ValueType) valueFormats {
+	formats := make(valueFormats, len(rows))
+	for _, vt := range rows {
+		formats[vt.Name] = valueFormat(vt.DisplayFormat, vt.Unit, vt.Step, vt.MinValue, vt.MaxValue)
+	}
+	return formats
+}
+
+func valueFormat(displayFormat string, unit pgtype.Text, step int32, min pgtype.Int4, max pgtype.Int4) utils.ValueFormat {
+	f := utils.ValueFormat{
+		DisplayFormat: displayFormat,
+		Unit:          unit.String,
+		Step:          int(step),
+	}
+	if min.Valid {
+		n := int(min.Int32)
+		f.Min = &n
+	}
+	if max.Valid {
+		n := int(max.Int32)
+		f.Max = &n
+	}
+	return f
+}
+
+func bossValueFormat(boss database.GetBossInfoRow) utils.ValueFormat {
+	return valueFormat(boss.DisplayFormat, boss.Unit, boss.Step, boss.MinValue, boss.MaxValue)
+}
+
+func formatUserRecords(formats valueFormats, records []models.UserRecord) {
+	for i, r := range records {
+		records[i].DisplayValue = formats.format(r.ValueType, int(r.Value))
+	}
+}
+
+func formatGuildRecords(formats valueFormats, records []models.GuildRecord) {
+	for i, r := range records {
+		records[i].DisplayValue = formats.format(r.ValueType, int(r.Value))
+	}
+}
@@ -2,19 +2,25 @@ package handlers
 
 import (
 	"context"
+	"strconv"
 
 	"tectonic-api/database"
 	"tectonic-api/logging"
 	"tectonic-api/models"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type CompetitionResponse struct {
-	Title            string                `json:"title"`
-	ParticipantCount int                   `json:"participant_count"`
-	Participants     []models.DetailedUser `json:"participants"`
-	Accounts         []string              `json:"accounts"`
-	Cutoff           int                   `json:"cutoff"`
-	PointsGiven      int                   `json:"points_given"`
+	Title            string                    `json:"title"`
+	ParticipantCount int                       `json:"participant_count"`
+	Participants     []models.DetailedUser     `json:"participants"`
+	Accounts         []string                  `json:"accounts"`
+	Cutoff           int                       `json:"cutoff"`
+	PointsGiven      int                       `json:"points_given"`
+	BasePoints       int                       `json:"base_points"`
+	Multiplier       int                       `json:"multiplier"`
+	PointUpdates     []models.UserPointsUpdate `json:"point_updates"`
 }
 
 type EndCompetitionInput struct {
@@ -39,6 +45,7 @@ func (s *Server) EndCompetition(ctx context.Context, input *EndCompetitionInput)
 		Accounts:         []string{},
 		Cutoff:           input.Cutoff,
 		PointsGiven:      0,
+		PointUpdates:     []models.UserPointsUpdate{},
 	}
 
 	if len(c.Participations) == 0 {
@@ -78,11 +85,17 @@ func (s *Server) EndCompetition(ctx context.Context, input *EndCompetitionInput)
 		Event:   "event_participation",
 		GuildID: input.GuildID,
 		UserIds: userIDs,
+		EventID: pgtype.Text{String: strconv.Itoa(input.CompetitionID), Valid: true},
 	})
 	if dbEi := database.ClassifyError(err); dbEi != nil {
 		return nil, s.dbError(*dbEi)
 	}
 
+	ranks, ei := database.WrapQuery(q.GetGuildRanks, ctx, input.GuildID)
+	if ei != nil {
+		return nil, s.dbError(*ei)
+	}
+
 	if err = tx.Commit(ctx); err != nil {
 		return nil, models.NewTectonicError(models.ERROR_API_UNAVAILABLE)
 	}
@@ -91,14 +104,21 @@ func (s *Server) EndCompetition(ctx context.Context, input *EndCompetitionInput)
 		logging.Get().Info("no activated users found in competition")
 	}
 
-	users, ei := s.getDetailedUsers(ctx, userIDs, input.GuildID)
+	season, err := s.getSeason(ctx, input.GuildID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	users, ei := s.getDetailedUsers(ctx, userIDs, input.GuildID, season.SeasonID, models.AllUserFields)
 	if ei != nil {
 		return nil, s.dbError(*ei)
 	}
 
-	var pointsGiven int
+	var pointsGiven, basePoints, multiplier int
 	if len(points) > 0 {
 		pointsGiven = int(points[0].GivenPoints)
+		basePoints = int(points[0].BasePoints)
+		multiplier = int(points[0].Multiplier)
 	}
 
 	return &EndCompetitionOutput{Body: CompetitionResponse{
@@ -108,6 +128,9 @@ func (s *Server) EndCompetition(ctx context.Context, input *EndCompetitionInput)
 		Accounts:         rsns,
 		Cutoff:           input.Cutoff,
 		PointsGiven:      pointsGiven,
+		BasePoints:       basePoints,
+		Multiplier:       multiplier,
+		PointUpdates:     models.PointsUpdatesFromEventRows(points, ranks),
 	}}, nil
 }
 
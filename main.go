@@ -1,9 +1,13 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"tectonic-api/config"
 	"tectonic-api/database"
@@ -48,21 +52,35 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Background jobs stop along with the server
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go srv.RunRecordPurge(ctx, cfg.RecordPurgeInterval)
+
 	r := chi.NewRouter()
 
 	r.Use(
 		logging.LoggingHandler,
 		middleware.CORS,
 		middleware.RateLimit,
-		middleware.Authentication(cfg),
+		middleware.Authentication(cfg, srv.Queries()),
 	)
 
 	routes.AttachV1Routes(r, srv)
 	logging.Get().Info("routes registered")
 
 	logging.Get().Info("server listening to requests", "port", cfg.Port)
-	err = http.ListenAndServe(":"+cfg.Port, r)
-	if err != nil {
+	server := &http.Server{Addr: ":" + cfg.Port, Handler: r}
+	go func() {
+		<-ctx.Done()
+		if err := server.Shutdown(context.Background()); err != nil {
+			logging.Get().Error("Server failed to shut down", "error", err)
+		}
+	}()
+
+	err = server.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		logging.Get().Error("Server failed to start", "error", err)
 	}
 }
@@ -1,21 +1,38 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"encoding/json"
 	"net/http"
+	"slices"
 	"strings"
 
 	"tectonic-api/config"
+	"tectonic-api/database"
 	"tectonic-api/logging"
 	"tectonic-api/models"
+	"tectonic-api/utils"
 )
 
-func Authentication(cfg *config.Config) func(http.Handler) http.Handler {
+const guildsPrefix = "/api/v1/guilds/"
+
+// Methods each API key role is allowed to use on its own guild's routes
+var roleMethods = map[models.APIKeyRole][]string{
+	models.APIKeyRoleReadOnly: {http.MethodGet},
+	models.APIKeyRoleBot:      {http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete},
+	models.APIKeyRoleAdmin:    {http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete},
+}
+
+// Guild routes that only compute a result from the request body, they're POST
+// but change nothing so read only keys may call them
+var readOnlyPosts = []string{
+	"ranks/assignments",
+}
+
+func Authentication(cfg *config.Config, queries *database.Queries) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		logging.Get().Debug("Adding authentication handler")
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			next.ServeHTTP(w, r)
-			return
 			// Skip auth for docs and OpenAPI spec
 			if !strings.HasPrefix(r.URL.Path, "/api/") {
 				next.ServeHTTP(w, r)
@@ -25,18 +42,82 @@ func Authentication(cfg *config.Config) func(http.Handler) http.Handler {
 			rlog := logging.Get().With("method", r.Method, "url", r.URL)
 
 			token := r.Header.Get("Authorization")
+			if token == "" {
+				rlog.Warn("Authentication key is missing")
+				writeError(w, models.ERROR_INVALID_TOKEN)
+				return
+			}
+
+			// The master key isn't scoped to a guild, it's used to create
+			// guilds and issue their first keys
+			if subtle.ConstantTimeCompare([]byte(token), []byte(cfg.APIKey)) == 1 {
+				rlog.Debug("Master API key is valid")
+				next.ServeHTTP(w, r)
+				return
+			}
 
 			rlog.Debug("Validating API key")
-			if token != cfg.APIKey {
-				rlog.Warn("Authentication key is invalid")
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusUnauthorized)
-				json.NewEncoder(w).Encode(models.NewTectonicError(models.ERROR_INVALID_TOKEN))
+			key, err := queries.GetAPIKeyByHash(r.Context(), utils.HashAPIKey(token))
+			if ei := database.ClassifyError(err); ei != nil {
+				if ei.Recoverable && ei.Code == "P0002" {
+					rlog.Warn("Authentication key is invalid")
+					writeError(w, models.ERROR_INVALID_TOKEN)
+					return
+				}
+				rlog.Error("Error validating API key", "error", ei.Error())
+				writeError(w, models.ERROR_API_UNAVAILABLE)
+				return
+			}
+
+			if !authorize(models.APIKeyRole(key.Role), key.GuildID, r.Method, r.URL.Path) {
+				rlog.Warn("API key is not allowed to access route", "key_id", key.KeyID, "role", key.Role)
+				writeError(w, models.ERROR_FORBIDDEN)
 				return
 			}
 
-			rlog.Debug("API key is valid")
+			rlog.Debug("API key is valid", "key_id", key.KeyID)
 			next.ServeHTTP(w, r)
 		})
 	}
 }
+
+// authorize decides whether a guild scoped key may call method on path.
+// Keys can read global data (bosses, categories, ...) but can only touch
+// routes under their own guild, managing keys and deleting the guild
+// itself is reserved for admin keys.
+func authorize(role models.APIKeyRole, keyGuildID string, method string, path string) bool {
+	guildID, rest := guildScope(path)
+	if guildID == "" {
+		return method == http.MethodGet
+	}
+	if guildID != keyGuildID {
+		return false
+	}
+
+	if rest == "keys" || strings.HasPrefix(rest, "keys/") || (rest == "" && method == http.MethodDelete) {
+		return role == models.APIKeyRoleAdmin
+	}
+
+	if method == http.MethodPost && slices.Contains(readOnlyPosts, rest) {
+		return slices.Contains(roleMethods[role], http.MethodGet)
+	}
+
+	return slices.Contains(roleMethods[role], method)
+}
+
+// guildScope splits "/api/v1/guilds/{guild_id}/rest" into the guild ID and
+// the rest of the path, the guild ID is empty for routes outside a guild
+func guildScope(path string) (string, string) {
+	if !strings.HasPrefix(path, guildsPrefix) {
+		return "", ""
+	}
+
+	guildID, rest, _ := strings.Cut(strings.TrimPrefix(path, guildsPrefix), "/")
+	return guildID, strings.TrimSuffix(rest, "/")
+}
+
+func writeError(w http.ResponseWriter, apiErr models.APIV1ErrorCode) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(apiErr.Status())
+	json.NewEncoder(w).Encode(models.NewTectonicError(apiErr))
+}
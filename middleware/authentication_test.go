@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	"tectonic-api/models"
+)
+
+func TestGuildScope(t *testing.T) {
+	tests := []struct {
+		path    string
+		guildID string
+		rest    string
+	}{
+		{"/api/v1/guilds", "", ""},
+		{"/api/v1/bosses", "", ""},
+		{"/api/v1/guilds/123", "123", ""},
+		{"/api/v1/guilds/123/", "123", ""},
+		{"/api/v1/guilds/123/keys", "123", "keys"},
+		{"/api/v1/guilds/123/users/456/points/split_low", "123", "users/456/points/split_low"},
+	}
+
+	for _, tt := range tests {
+		guildID, rest := guildScope(tt.path)
+		if guildID != tt.guildID || rest != tt.rest {
+			t.Errorf("guildScope(%q) = (%q, %q); want (%q, %q)", tt.path, guildID, rest, tt.guildID, tt.rest)
+		}
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	const guild = "123"
+
+	tests := []struct {
+		name   string
+		role   models.APIKeyRole
+		method string
+		path   string
+		want   bool
+	}{
+		{"read only can read guild", models.APIKeyRoleReadOnly, http.MethodGet, "/api/v1/guilds/123/users/1", true},
+		{"read only can't write", models.APIKeyRoleReadOnly, http.MethodPut, "/api/v1/guilds/123/users/1/points/custom/5", false},
+		{"read only can diff rank roles", models.APIKeyRoleReadOnly, http.MethodPost, "/api/v1/guilds/123/ranks/assignments", true},
+		{"read only can't add ranks", models.APIKeyRoleReadOnly, http.MethodPost, "/api/v1/guilds/123/ranks", false},
+		{"bot can write", models.APIKeyRoleBot, http.MethodPost, "/api/v1/guilds/123/records", true},
+		{"bot can delete records", models.APIKeyRoleBot, http.MethodDelete, "/api/v1/guilds/123/records/id/4", true},
+		{"other guild is rejected", models.APIKeyRoleAdmin, http.MethodGet, "/api/v1/guilds/999/users/1", false},
+		{"global data is readable", models.APIKeyRoleReadOnly, http.MethodGet, "/api/v1/bosses", true},
+		{"guilds can't be created", models.APIKeyRoleAdmin, http.MethodPost, "/api/v1/guilds", false},
+		{"catalog can't be edited", models.APIKeyRoleAdmin, http.MethodPut, "/api/v1/bosses/vorkath", false},
+		{"bot can't manage keys", models.APIKeyRoleBot, http.MethodGet, "/api/v1/guilds/123/keys", false},
+		{"admin can manage keys", models.APIKeyRoleAdmin, http.MethodPost, "/api/v1/guilds/123/keys/1/rotate", true},
+		{"bot can't delete guild", models.APIKeyRoleBot, http.MethodDelete, "/api/v1/guilds/123", false},
+		{"admin can delete guild", models.APIKeyRoleAdmin, http.MethodDelete, "/api/v1/guilds/123", true},
+		{"unknown role is rejected", models.APIKeyRole("owner"), http.MethodGet, "/api/v1/guilds/123", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := authorize(tt.role, guild, tt.method, tt.path); got != tt.want {
+				t.Errorf("authorize(%s, %s, %s) = %t; want %t", tt.role, tt.method, tt.path, got, tt.want)
+			}
+		})
+	}
+}
@@ -22,6 +22,7 @@ const (
 	ERROR_WRONG_BODY                              // Body is malformated, please check docs for example on how to send the request
 	ERROR_VALIDATION_FAILED                       // Request validation failed
 	ERROR_INVALID_TOKEN                           // Your token is invalid
+	ERROR_FORBIDDEN                               // Your token doesn't have access to this resource
 )
 
 // Model-based errors
@@ -78,6 +79,30 @@ const (
 
 	ERROR_GUILD_RANK_NOT_FOUND // Guild rank not found
 	ERROR_GUILD_RANK_EXISTS    // Guild rank already exists
+
+	ERROR_API_KEY_NOT_FOUND // API key not found
+	ERROR_API_KEY_EXISTS    // API key already exists
+
+	ERROR_POINT_TRANSACTION_NOT_FOUND // Point transaction not found
+	ERROR_POINT_TRANSACTION_REVOKED   // Point transaction has already been revoked
+
+	ERROR_MULTIPLIER_WINDOW_NOT_FOUND // Multiplier window not found
+
+	ERROR_POINT_SOURCE_IN_USE // Point source is still used by combat achievements
+
+	ERROR_RECORD_NOT_PENDING // Record has already been reviewed
+
+	ERROR_SEASON_NOT_FOUND // Season not found
+
+	ERROR_BOSS_IN_USE     // Boss still has records
+	ERROR_CATEGORY_IN_USE // Category still has bosses
+
+	ERROR_VALUE_TYPE_NOT_FOUND // Value type not found
+
+	ERROR_GUILD_BOSS_DISABLED // Boss is disabled for this guild
+
+	ERROR_BOSS_RETIRED      // Boss is retired and takes no new records
+	ERROR_VALUE_TYPE_EXISTS // Value type already exists
 )
 
 // Server errors
@@ -93,6 +118,8 @@ func (e APIV1ErrorCode) Status() int {
 	switch e {
 	case ERROR_INVALID_TOKEN:
 		return http.StatusUnauthorized
+	case ERROR_FORBIDDEN:
+		return http.StatusForbidden
 	case ERROR_WOM_UNAVAILABLE:
 		return http.StatusServiceUnavailable
 	case ERROR_API_UNAVAILABLE, ERROR_API_DEAD:
@@ -119,7 +146,12 @@ func (e APIV1ErrorCode) Status() int {
 		ERROR_USER_ACHIEVEMENT_NOT_FOUND,
 		ERROR_POINT_SOURCE_NOT_FOUND,
 		ERROR_COMBAT_ACHIEVEMENT_NOT_FOUND,
-		ERROR_GUILD_RANK_NOT_FOUND:
+		ERROR_GUILD_RANK_NOT_FOUND,
+		ERROR_API_KEY_NOT_FOUND,
+		ERROR_POINT_TRANSACTION_NOT_FOUND,
+		ERROR_MULTIPLIER_WINDOW_NOT_FOUND,
+		ERROR_SEASON_NOT_FOUND,
+		ERROR_VALUE_TYPE_NOT_FOUND:
 		return http.StatusNotFound
 
 	case ERROR_GUILD_EXISTS,
@@ -139,7 +171,16 @@ func (e APIV1ErrorCode) Status() int {
 		ERROR_USER_ACHIEVEMENT_EXISTS,
 		ERROR_POINT_SOURCE_EXISTS,
 		ERROR_COMBAT_ACHIEVEMENT_EXISTS,
-		ERROR_GUILD_RANK_EXISTS:
+		ERROR_GUILD_RANK_EXISTS,
+		ERROR_API_KEY_EXISTS,
+		ERROR_POINT_TRANSACTION_REVOKED,
+		ERROR_POINT_SOURCE_IN_USE,
+		ERROR_RECORD_NOT_PENDING,
+		ERROR_BOSS_IN_USE,
+		ERROR_CATEGORY_IN_USE,
+		ERROR_GUILD_BOSS_DISABLED,
+		ERROR_BOSS_RETIRED,
+		ERROR_VALUE_TYPE_EXISTS:
 		return http.StatusConflict
 	}
 
@@ -0,0 +1,189 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"tectonic-api/database"
+)
+
+// TiePolicy decides the positions of records with equal values, it mirrors
+// the record_rankings view so the API and the board always agree
+type TiePolicy string
+
+const (
+	// The earlier achiever takes the higher position
+	TiePolicyEarliest TiePolicy = "earliest"
+	// Tied records share a position and the next one is skipped, like RANK()
+	TiePolicyShared TiePolicy = "shared"
+)
+
+// RankedRecord is a record's place in a boss ranking
+type RankedRecord struct {
+	Position int       `json:"position"`
+	RecordID int32     `json:"record_id"`
+	Value    int32     `json:"value"`
+	Date     time.Time `json:"date"`
+	UserIDs  []string  `json:"user_ids"`
+}
+
+// RankBossRecords orders the records of a boss the same way the guild board
+// does, best per user for solo bosses and every record for team bosses.
+// The record with id exclude is left out, pass 0 to rank everything.
+func RankBossRecords(rows []database.GetBossRecordsRow, boss database.GetBossInfoRow, policy TiePolicy, exclude int32) []RankedRecord {
+	// Group rows by record_id, rows of a record are contiguous
+	var records []RankedRecord
+	for _, row := range rows {
+		if row.RecordID == exclude {
+			continue
+		}
+		n := len(records)
+		if n > 0 && records[n-1].RecordID == row.RecordID {
+			records[n-1].UserIDs = append(records[n-1].UserIDs, row.UserID)
+			continue
+		}
+		records = append(records, RankedRecord{
+			RecordID: row.RecordID,
+			Value:    row.Value,
+			Date:     row.Date.Time,
+			UserIDs:  []string{row.UserID},
+		})
+	}
+
+	better := func(a, b RankedRecord) int {
+		if a.Value != b.Value {
+			if isBetter(a.Value, b.Value, boss.HigherIsBetter) {
+				return -1
+			}
+			return 1
+		}
+		// Equal values keep the earlier achiever ahead, then the earlier submission
+		if c := a.Date.Compare(b.Date); c != 0 {
+			return c
+		}
+		if a.RecordID < b.RecordID {
+			return -1
+		}
+		if a.RecordID > b.RecordID {
+			return 1
+		}
+		return 0
+	}
+
+	if boss.Solo {
+		// For solo bosses: pick each user's best record
+		best := make(map[string]RankedRecord)
+		for _, r := range records {
+			userID := r.UserIDs[0]
+			if existing, ok := best[userID]; !ok || better(r, existing) < 0 {
+				best[userID] = r
+			}
+		}
+		records = records[:0]
+		for _, r := range best {
+			records = append(records, r)
+		}
+	}
+
+	slices.SortFunc(records, better)
+	for i := range records {
+		records[i].Position = i + 1
+		if policy == TiePolicyShared && i > 0 && records[i].Value == records[i-1].Value {
+			records[i].Position = records[i-1].Position
+		}
+	}
+	return records
+}
+
+func isBetter(a, b int32, higherIsBetter bool) bool {
+	if higherIsBetter {
+		return a > b
+	}
+	return a < b
+}
+
+// RecordMove is a record whose place changed because of another record,
+// NewPosition is 0 when it left the ranking altogether
+type RecordMove struct {
+	RecordID    int32    `json:"record_id"`
+	Value       int32    `json:"value"`
+	UserIDs     []string `json:"user_ids"`
+	OldPosition int      `json:"position_old"`
+	NewPosition int      `json:"position_new"`
+}
+
+// RecordPlacement describes how a record changed the top of a boss ranking
+type RecordPlacement struct {
+	Position int
+	OldValue int
+	Before   []RankedRecord
+	After    []RankedRecord
+	Moved    []RecordMove
+	Dropped  []RecordMove
+}
+
+// PlaceRecord ranks the boss with and without recordID and compares the top
+// topN positions of both
+func PlaceRecord(recordID int32, rows []database.GetBossRecordsRow, boss database.GetBossInfoRow, policy TiePolicy, topN int) RecordPlacement {
+	before := RankBossRecords(rows, boss, policy, recordID)
+	after := RankBossRecords(rows, boss, policy, 0)
+
+	p := RecordPlacement{
+		Before:  topRecords(before, topN),
+		After:   topRecords(after, topN),
+		Moved:   []RecordMove{},
+		Dropped: []RecordMove{},
+	}
+
+	positions := make(map[int32]int, len(after))
+	for _, r := range after {
+		positions[r.RecordID] = r.Position
+	}
+
+	p.Position = positions[recordID]
+	if p.Position > 0 && p.Position <= len(before) {
+		// The value that was at this position before the record was added
+		p.OldValue = int(before[p.Position-1].Value)
+	}
+
+	for _, r := range p.Before {
+		newPosition := positions[r.RecordID]
+		if newPosition == r.Position {
+			continue
+		}
+		move := RecordMove{
+			RecordID:    r.RecordID,
+			Value:       r.Value,
+			UserIDs:     r.UserIDs,
+			OldPosition: r.Position,
+			NewPosition: newPosition,
+		}
+		if newPosition == 0 || newPosition > topN {
+			p.Dropped = append(p.Dropped, move)
+		} else {
+			p.Moved = append(p.Moved, move)
+		}
+	}
+	return p
+}
+
+// RecordPosition is the 1-based position of a record, or 0 when it isn't
+// ranked (e.g. the user has a better record for a solo boss)
+func RecordPosition(recordID int32, rows []database.GetBossRecordsRow, boss database.GetBossInfoRow, policy TiePolicy) int {
+	for _, r := range RankBossRecords(rows, boss, policy, 0) {
+		if r.RecordID == recordID {
+			return r.Position
+		}
+	}
+	return 0
+}
+
+// topRecords keeps the records placed within the first n positions, with
+// shared positions that can be more than n records
+func topRecords(records []RankedRecord, n int) []RankedRecord {
+	i := 0
+	for i < len(records) && records[i].Position <= n {
+		i++
+	}
+	return records[:i]
+}
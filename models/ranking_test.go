@@ -0,0 +1,238 @@
+package models
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"tectonic-api/database"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	soloTimeBoss  = database.GetBossInfoRow{Name: "vardorvis", Solo: true, ValueType: "time"}
+	teamTimeBoss  = database.GetBossInfoRow{Name: "cox_3", Solo: false, ValueType: "time"}
+	soloDepthBoss = database.GetBossInfoRow{Name: "delve", Solo: true, ValueType: "depth", HigherIsBetter: true}
+)
+
+func recordIDs(records []RankedRecord) []int32 {
+	ids := make([]int32, len(records))
+	for i, r := range records {
+		ids[i] = r.RecordID
+	}
+	return ids
+}
+
+func TestRankBossRecords(t *testing.T) {
+	rows := []database.GetBossRecordsRow{
+		{RecordID: 1, Value: 120, UserID: "a"},
+		{RecordID: 2, Value: 100, UserID: "b"},
+		{RecordID: 3, Value: 90, UserID: "a"},
+		{RecordID: 4, Value: 110, UserID: "c"},
+	}
+
+	tests := []struct {
+		name     string
+		boss     database.GetBossInfoRow
+		exclude  int32
+		expected []int32
+	}{
+		{name: "Solo keeps best per user", boss: soloTimeBoss, expected: []int32{3, 2, 4}},
+		{name: "Solo falls back when best is excluded", boss: soloTimeBoss, exclude: 3, expected: []int32{2, 4, 1}},
+		{name: "Team ranks every record", boss: teamTimeBoss, expected: []int32{3, 2, 4, 1}},
+		{name: "Higher is better", boss: soloDepthBoss, expected: []int32{1, 4, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RankBossRecords(rows, tt.boss, TiePolicyEarliest, tt.exclude)
+			if ids := recordIDs(got); !slices.Equal(ids, tt.expected) {
+				t.Errorf("RankBossRecords() = %v, expected %v", ids, tt.expected)
+			}
+			for i, r := range got {
+				if r.Position != i+1 {
+					t.Errorf("record %d has position %d, expected %d", r.RecordID, r.Position, i+1)
+				}
+			}
+		})
+	}
+}
+
+func TestRankBossRecordsGroupsTeams(t *testing.T) {
+	rows := []database.GetBossRecordsRow{
+		{RecordID: 1, Value: 100, UserID: "a"},
+		{RecordID: 1, Value: 100, UserID: "b"},
+		{RecordID: 2, Value: 100, UserID: "c"},
+	}
+
+	got := RankBossRecords(rows, teamTimeBoss, TiePolicyEarliest, 0)
+	if len(got) != 2 {
+		t.Fatalf("RankBossRecords() returned %d records, expected 2", len(got))
+	}
+	if !slices.Equal(got[0].UserIDs, []string{"a", "b"}) {
+		t.Errorf("team = %v, expected [a b]", got[0].UserIDs)
+	}
+	// Equal values keep the earlier record ahead
+	if got[0].RecordID != 1 {
+		t.Errorf("first record = %d, expected 1", got[0].RecordID)
+	}
+}
+
+func TestRankBossRecordsTiesByDate(t *testing.T) {
+	day := func(d int) pgtype.Timestamp {
+		return pgtype.Timestamp{Time: time.Date(2025, time.January, d, 0, 0, 0, 0, time.UTC), Valid: true}
+	}
+	rows := []database.GetBossRecordsRow{
+		{RecordID: 1, Value: 100, UserID: "a", Date: day(10)},
+		{RecordID: 2, Value: 100, UserID: "b", Date: day(3)},
+		{RecordID: 3, Value: 100, UserID: "c", Date: day(3)},
+	}
+
+	// A backdated record submitted later still ranks ahead of a newer tie
+	got := RankBossRecords(rows, soloTimeBoss, TiePolicyEarliest, 0)
+	if ids := recordIDs(got); !slices.Equal(ids, []int32{2, 3, 1}) {
+		t.Errorf("RankBossRecords() = %v, expected [2 3 1]", ids)
+	}
+}
+
+func TestRankBossRecordsSharedPositions(t *testing.T) {
+	rows := []database.GetBossRecordsRow{
+		{RecordID: 1, Value: 100, UserID: "a"},
+		{RecordID: 2, Value: 90, UserID: "b"},
+		{RecordID: 3, Value: 100, UserID: "c"},
+		{RecordID: 4, Value: 110, UserID: "d"},
+	}
+
+	tests := []struct {
+		name      string
+		policy    TiePolicy
+		positions []int
+	}{
+		{name: "Earliest", policy: TiePolicyEarliest, positions: []int{1, 2, 3, 4}},
+		{name: "Shared", policy: TiePolicyShared, positions: []int{1, 2, 2, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RankBossRecords(rows, soloTimeBoss, tt.policy, 0)
+			// Order is the same under both policies, only positions differ
+			if ids := recordIDs(got); !slices.Equal(ids, []int32{2, 1, 3, 4}) {
+				t.Errorf("RankBossRecords() = %v, expected [2 1 3 4]", ids)
+			}
+			positions := make([]int, len(got))
+			for i, r := range got {
+				positions[i] = r.Position
+			}
+			if !slices.Equal(positions, tt.positions) {
+				t.Errorf("positions = %v, expected %v", positions, tt.positions)
+			}
+		})
+	}
+}
+
+func TestPlaceRecordSharedTie(t *testing.T) {
+	rows := []database.GetBossRecordsRow{
+		{RecordID: 1, Value: 100, UserID: "a"},
+		{RecordID: 2, Value: 110, UserID: "b"},
+		{RecordID: 3, Value: 100, UserID: "c"},
+	}
+
+	p := PlaceRecord(3, rows, soloTimeBoss, TiePolicyShared, 2)
+
+	if p.Position != 1 {
+		t.Errorf("Position = %d, expected 1", p.Position)
+	}
+	// Tying the top record doesn't move it, and both tied records fit in the top 2
+	if ids := recordIDs(p.After); !slices.Equal(ids, []int32{1, 3}) {
+		t.Errorf("After = %v, expected [1 3]", ids)
+	}
+	if len(p.Moved) != 0 {
+		t.Errorf("Moved = %+v, expected no moves", p.Moved)
+	}
+	if len(p.Dropped) != 1 || p.Dropped[0].RecordID != 2 || p.Dropped[0].NewPosition != 3 {
+		t.Errorf("Dropped = %+v, expected record 2 dropping to 3", p.Dropped)
+	}
+}
+
+func TestTopRecordsKeepsSharedPositions(t *testing.T) {
+	records := []RankedRecord{
+		{RecordID: 1, Position: 1},
+		{RecordID: 2, Position: 2},
+		{RecordID: 3, Position: 2},
+		{RecordID: 4, Position: 4},
+	}
+
+	if ids := recordIDs(topRecords(records, 2)); !slices.Equal(ids, []int32{1, 2, 3}) {
+		t.Errorf("topRecords(2) = %v, expected [1 2 3]", ids)
+	}
+	if ids := recordIDs(topRecords(records, 0)); len(ids) != 0 {
+		t.Errorf("topRecords(0) = %v, expected none", ids)
+	}
+}
+
+func TestPlaceRecord(t *testing.T) {
+	rows := []database.GetBossRecordsRow{
+		{RecordID: 1, Value: 100, UserID: "a"},
+		{RecordID: 2, Value: 110, UserID: "b"},
+		{RecordID: 3, Value: 120, UserID: "c"},
+		{RecordID: 4, Value: 105, UserID: "d"},
+	}
+
+	p := PlaceRecord(4, rows, soloTimeBoss, TiePolicyEarliest, 3)
+
+	if p.Position != 2 {
+		t.Errorf("Position = %d, expected 2", p.Position)
+	}
+	if p.OldValue != 110 {
+		t.Errorf("OldValue = %d, expected 110", p.OldValue)
+	}
+	if ids := recordIDs(p.Before); !slices.Equal(ids, []int32{1, 2, 3}) {
+		t.Errorf("Before = %v, expected [1 2 3]", ids)
+	}
+	if ids := recordIDs(p.After); !slices.Equal(ids, []int32{1, 4, 2}) {
+		t.Errorf("After = %v, expected [1 4 2]", ids)
+	}
+	if len(p.Moved) != 1 || p.Moved[0].RecordID != 2 || p.Moved[0].OldPosition != 2 || p.Moved[0].NewPosition != 3 {
+		t.Errorf("Moved = %+v, expected record 2 from 2 to 3", p.Moved)
+	}
+	if len(p.Dropped) != 1 || p.Dropped[0].RecordID != 3 || p.Dropped[0].NewPosition != 4 {
+		t.Errorf("Dropped = %+v, expected record 3 dropping to 4", p.Dropped)
+	}
+}
+
+func TestPlaceRecordReplacesOwnRecord(t *testing.T) {
+	rows := []database.GetBossRecordsRow{
+		{RecordID: 1, Value: 100, UserID: "a"},
+		{RecordID: 2, Value: 110, UserID: "b"},
+		{RecordID: 3, Value: 95, UserID: "b"},
+	}
+
+	p := PlaceRecord(3, rows, soloTimeBoss, TiePolicyEarliest, 3)
+
+	if p.Position != 1 {
+		t.Errorf("Position = %d, expected 1", p.Position)
+	}
+	if len(p.Dropped) != 1 || p.Dropped[0].RecordID != 2 || p.Dropped[0].NewPosition != 0 {
+		t.Errorf("Dropped = %+v, expected the user's previous record to leave the ranking", p.Dropped)
+	}
+	if len(p.Moved) != 1 || p.Moved[0].RecordID != 1 {
+		t.Errorf("Moved = %+v, expected record 1 to move down", p.Moved)
+	}
+}
+
+func TestPlaceRecordOutsideTop(t *testing.T) {
+	rows := []database.GetBossRecordsRow{
+		{RecordID: 1, Value: 100, UserID: "a"},
+		{RecordID: 2, Value: 150, UserID: "b"},
+	}
+
+	p := PlaceRecord(2, rows, soloTimeBoss, TiePolicyEarliest, 1)
+
+	if p.Position != 2 || p.OldValue != 0 {
+		t.Errorf("Position = %d, OldValue = %d, expected 2 and 0", p.Position, p.OldValue)
+	}
+	if len(p.Moved) != 0 || len(p.Dropped) != 0 {
+		t.Errorf("expected no movement, got moved %+v dropped %+v", p.Moved, p.Dropped)
+	}
+}
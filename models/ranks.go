@@ -0,0 +1,221 @@
+package models
+
+import (
+	"tectonic-api/database"
+)
+
+// UserTierFromRank converts a guild rank row into the tier shape returned to clients
+func UserTierFromRank(rank database.GetGuildRanksRow) UserTier {
+	t := UserTier{
+		Name:         rank.Name,
+		MinPoints:    rank.MinPoints,
+		DisplayOrder: rank.DisplayOrder,
+	}
+	if rank.Icon.Valid {
+		t.Icon = &rank.Icon.String
+	}
+	if rank.RoleID.Valid {
+		t.RoleID = &rank.RoleID.String
+	}
+	return t
+}
+
+// TierForPoints resolves the tier a user with the given points belongs to:
+// the rank with the highest min_points that doesn't exceed points. Returns
+// nil if no rank applies.
+func TierForPoints(ranks []database.GetGuildRanksRow, points int32) *UserTier {
+	var best *database.GetGuildRanksRow
+	for i := range ranks {
+		if ranks[i].MinPoints > points {
+			continue
+		}
+		if best == nil || ranks[i].MinPoints > best.MinPoints {
+			best = &ranks[i]
+		}
+	}
+
+	if best == nil {
+		return nil
+	}
+	t := UserTierFromRank(*best)
+	return &t
+}
+
+// RankChange - how a points change moved a user between guild rank tiers
+type RankChange struct {
+	PreviousTier *UserTier `json:"previous_tier,omitempty"`
+	NewTier      *UserTier `json:"new_tier,omitempty"`
+	Changed      bool      `json:"changed"`
+	AddRoleID    *string   `json:"add_role_id,omitempty"`
+	RemoveRoleID *string   `json:"remove_role_id,omitempty"`
+}
+
+// NewRankChange compares the tiers before and after a points change and
+// works out which Discord role should be added and removed
+func NewRankChange(ranks []database.GetGuildRanksRow, previousPoints int32, points int32) RankChange {
+	c := RankChange{
+		PreviousTier: TierForPoints(ranks, previousPoints),
+		NewTier:      TierForPoints(ranks, points),
+	}
+
+	prevName, newName := tierName(c.PreviousTier), tierName(c.NewTier)
+	if prevName == newName {
+		return c
+	}
+
+	c.Changed = true
+	prevRole, newRole := tierRoleID(c.PreviousTier), tierRoleID(c.NewTier)
+	if prevRole != newRole {
+		if newRole != "" {
+			c.AddRoleID = &newRole
+		}
+		if prevRole != "" {
+			c.RemoveRoleID = &prevRole
+		}
+	}
+	return c
+}
+
+func tierName(t *UserTier) string {
+	if t == nil {
+		return ""
+	}
+	return t.Name
+}
+
+func tierRoleID(t *UserTier) string {
+	if t == nil || t.RoleID == nil {
+		return ""
+	}
+	return *t.RoleID
+}
+
+// UserPointsUpdate - returned for every user affected by a points change
+type UserPointsUpdate struct {
+	UserID      string     `json:"user_id"`
+	GuildID     string     `json:"guild_id"`
+	Points      int32      `json:"points"`
+	GivenPoints int32      `json:"given_points"`
+	BasePoints  int32      `json:"base_points"`
+	Multiplier  int32      `json:"multiplier"`
+	BatchID     int32      `json:"batch_id"`
+	RankChange  RankChange `json:"rank_change"`
+}
+
+func PointsUpdatesFromEventRows(rows []database.UpdatePointsByEventRow, ranks []database.GetGuildRanksRow) []UserPointsUpdate {
+	result := make([]UserPointsUpdate, len(rows))
+	for i, row := range rows {
+		result[i] = UserPointsUpdate{
+			UserID:      row.UserID,
+			GuildID:     row.GuildID,
+			Points:      row.Points,
+			GivenPoints: row.GivenPoints,
+			BasePoints:  row.BasePoints,
+			Multiplier:  row.Multiplier,
+			BatchID:     row.BatchID,
+			RankChange:  NewRankChange(ranks, row.Points-row.GivenPoints, row.Points),
+		}
+	}
+	return result
+}
+
+func PointsUpdatesFromCustomRows(rows []database.UpdatePointsCustomRow, ranks []database.GetGuildRanksRow) []UserPointsUpdate {
+	result := make([]UserPointsUpdate, len(rows))
+	for i, row := range rows {
+		result[i] = UserPointsUpdate{
+			UserID:      row.UserID,
+			GuildID:     row.GuildID,
+			Points:      row.Points,
+			GivenPoints: row.GivenPoints,
+			BasePoints:  row.GivenPoints,
+			Multiplier:  1,
+			BatchID:     row.BatchID,
+			RankChange:  NewRankChange(ranks, row.Points-row.GivenPoints, row.Points),
+		}
+	}
+	return result
+}
+
+// RankAssignment - the rank role a user should hold, and when the roles they
+// currently hold are known, which roles to add and remove to get there
+type RankAssignment struct {
+	UserID        string    `json:"user_id"`
+	Points        int32     `json:"points"`
+	Tier          *UserTier `json:"tier,omitempty"`
+	RoleID        *string   `json:"role_id,omitempty"`
+	AddRoleIDs    []string  `json:"add_role_ids,omitempty"`
+	RemoveRoleIDs []string  `json:"remove_role_ids,omitempty"`
+}
+
+func RankAssignmentsFromRows(rows []database.GetGuildUserPointsRow, ranks []database.GetGuildRanksRow) []RankAssignment {
+	result := make([]RankAssignment, len(rows))
+	for i, row := range rows {
+		tier := TierForPoints(ranks, row.Points)
+		result[i] = RankAssignment{
+			UserID: row.UserID,
+			Points: row.Points,
+			Tier:   tier,
+		}
+		if tier != nil {
+			result[i].RoleID = tier.RoleID
+		}
+	}
+	return result
+}
+
+// RankRoleDiff compares assignments against the roles members currently
+// hold and returns only the members whose rank roles are out of sync. Roles
+// that don't belong to any guild rank are left alone, as are members that
+// aren't registered users.
+func RankRoleDiff(assignments []RankAssignment, members []MemberRoles, ranks []database.GetGuildRanksRow) []RankAssignment {
+	rankRoles := make(map[string]bool)
+	for _, r := range ranks {
+		if r.RoleID.Valid {
+			rankRoles[r.RoleID.String] = true
+		}
+	}
+
+	held := make(map[string][]DiscordSnowflake, len(members))
+	for _, m := range members {
+		held[string(m.UserID)] = m.RoleIDs
+	}
+
+	diff := make([]RankAssignment, 0)
+	for _, a := range assignments {
+		roles, ok := held[a.UserID]
+		if !ok {
+			continue
+		}
+
+		expected := ""
+		if a.RoleID != nil {
+			expected = *a.RoleID
+		}
+
+		hasExpected := false
+		remove := make([]string, 0)
+		for _, role := range roles {
+			id := string(role)
+			if id == expected {
+				hasExpected = true
+				continue
+			}
+			if rankRoles[id] {
+				remove = append(remove, id)
+			}
+		}
+
+		add := make([]string, 0)
+		if expected != "" && !hasExpected {
+			add = append(add, expected)
+		}
+
+		if len(add) == 0 && len(remove) == 0 {
+			continue
+		}
+		a.AddRoleIDs = add
+		a.RemoveRoleIDs = remove
+		diff = append(diff, a)
+	}
+	return diff
+}
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"slices"
+	"testing"
+
+	"tectonic-api/database"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var testRanks = []database.GetGuildRanksRow{
+	{Name: "Bronze", MinPoints: 0, RoleID: pgtype.Text{String: "100", Valid: true}},
+	{Name: "Silver", MinPoints: 100, RoleID: pgtype.Text{String: "200", Valid: true}},
+	{Name: "Gold", MinPoints: 250},
+	{Name: "Platinum", MinPoints: 500, RoleID: pgtype.Text{String: "400", Valid: true}},
+}
+
+func TestTierForPoints(t *testing.T) {
+	tests := []struct {
+		name     string
+		points   int32
+		expected string
+	}{
+		{name: "Lowest tier", points: 0, expected: "Bronze"},
+		{name: "Just below threshold", points: 99, expected: "Bronze"},
+		{name: "Exactly on threshold", points: 100, expected: "Silver"},
+		{name: "Highest tier", points: 10000, expected: "Platinum"},
+		{name: "Below every tier", points: -5, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tierName(TierForPoints(testRanks, tt.points)); got != tt.expected {
+				t.Errorf("TierForPoints(%d) = %q, expected %q", tt.points, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewRankChange(t *testing.T) {
+	tests := []struct {
+		name       string
+		previous   int32
+		points     int32
+		changed    bool
+		addRole    string
+		removeRole string
+	}{
+		{name: "Same tier", previous: 10, points: 50, changed: false},
+		{name: "Promotion", previous: 90, points: 110, changed: true, addRole: "200", removeRole: "100"},
+		{name: "Demotion", previous: 110, points: 90, changed: true, addRole: "100", removeRole: "200"},
+		{name: "Promotion to tier without role", previous: 200, points: 260, changed: true, removeRole: "200"},
+		{name: "Skipping tiers", previous: 10, points: 600, changed: true, addRole: "400", removeRole: "100"},
+		{name: "Into first tier", previous: -10, points: 0, changed: true, addRole: "100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewRankChange(testRanks, tt.previous, tt.points)
+			if c.Changed != tt.changed {
+				t.Errorf("Changed = %t, expected %t", c.Changed, tt.changed)
+			}
+			if got := derefString(c.AddRoleID); got != tt.addRole {
+				t.Errorf("AddRoleID = %q, expected %q", got, tt.addRole)
+			}
+			if got := derefString(c.RemoveRoleID); got != tt.removeRole {
+				t.Errorf("RemoveRoleID = %q, expected %q", got, tt.removeRole)
+			}
+		})
+	}
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func TestRankRoleDiff(t *testing.T) {
+	users := []database.GetGuildUserPointsRow{
+		{UserID: "1", Points: 600},
+		{UserID: "2", Points: 150},
+		{UserID: "3", Points: 300},
+		{UserID: "4", Points: 10},
+		{UserID: "5", Points: 10},
+	}
+	members := []MemberRoles{
+		// Promoted, still holds the old rank role and an unrelated role
+		{UserID: "1", RoleIDs: []DiscordSnowflake{"100", "999"}},
+		// Already in sync
+		{UserID: "2", RoleIDs: []DiscordSnowflake{"200"}},
+		// Gold has no role, the Silver role should go
+		{UserID: "3", RoleIDs: []DiscordSnowflake{"200"}},
+		// Missing their role entirely
+		{UserID: "4", RoleIDs: []DiscordSnowflake{}},
+		// Not a registered user
+		{UserID: "6", RoleIDs: []DiscordSnowflake{"400"}},
+	}
+
+	diff := RankRoleDiff(RankAssignmentsFromRows(users, testRanks), members, testRanks)
+
+	expected := map[string][2][]string{
+		"1": {{"400"}, {"100"}},
+		"3": {{}, {"200"}},
+		"4": {{"100"}, {}},
+	}
+
+	if len(diff) != len(expected) {
+		t.Fatalf("got %d out of sync members, expected %d", len(diff), len(expected))
+	}
+	for _, a := range diff {
+		e, ok := expected[a.UserID]
+		if !ok {
+			t.Errorf("user %s should be in sync", a.UserID)
+			continue
+		}
+		if !slices.Equal(a.AddRoleIDs, e[0]) {
+			t.Errorf("user %s AddRoleIDs = %v, expected %v", a.UserID, a.AddRoleIDs, e[0])
+		}
+		if !slices.Equal(a.RemoveRoleIDs, e[1]) {
+			t.Errorf("user %s RemoveRoleIDs = %v, expected %v", a.UserID, a.RemoveRoleIDs, e[1])
+		}
+	}
+}
@@ -1,13 +1,157 @@
 package models
 
+import (
+	"net/url"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
 type InputGuild struct {
 	GuildID DiscordSnowflake `json:"guild_id"`
 }
 
 type InputRecord struct {
-	Value    int                `json:"value"      minimum:"1"`
-	BossName string             `json:"boss_name"  minLength:"1" maxLength:"50"`
-	UserIDs  []DiscordSnowflake `json:"user_ids"   minItems:"1"  maxItems:"8"`
+	Value       int                `json:"value,omitempty" minimum:"1" maximum:"2147483647"`
+	Time        string             `json:"time,omitempty"  maxLength:"16" doc:"Human readable time for time based bosses, e.g. 1:23.40 or 01:02:03.00"`
+	BossName    string             `json:"boss_name"  minLength:"1" maxLength:"65"`
+	UserIDs     []DiscordSnowflake `json:"user_ids"   minItems:"1"  maxItems:"8"`
+	SubmittedBy *DiscordSnowflake  `json:"submitted_by,omitempty"`
+	Evidence    []InputEvidence    `json:"evidence,omitempty" maxItems:"8"`
+	Date        *time.Time         `json:"date,omitempty" doc:"When the record was achieved, defaults to now"`
+}
+
+func (r InputRecord) Resolve(ctx huma.Context, prefix *huma.PathBuffer) []error {
+	var errs []error
+	if (r.Value == 0) == (r.Time == "") {
+		errs = append(errs, &huma.ErrorDetail{
+			Location: prefix.With("value"),
+			Message:  "exactly one of value or time must be set",
+			Value:    r.Value,
+		})
+	}
+	if r.Date != nil && r.Date.After(time.Now()) {
+		errs = append(errs, &huma.ErrorDetail{
+			Location: prefix.With("date"),
+			Message:  "date can't be in the future",
+			Value:    *r.Date,
+		})
+	}
+	return errs
+}
+
+type UpdateRecordBody struct {
+	Value    *int               `json:"value,omitempty"     minimum:"1" maximum:"2147483647"`
+	Time     *string            `json:"time,omitempty"      maxLength:"16" doc:"Human readable time for time based bosses, e.g. 1:23.40"`
+	Date     *time.Time         `json:"date,omitempty"`
+	BossName *string            `json:"boss_name,omitempty" minLength:"1" maxLength:"65"`
+	UserIDs  []DiscordSnowflake `json:"user_ids,omitempty"  maxItems:"8" doc:"Replaces the whole team when set"`
+}
+
+func (b UpdateRecordBody) Resolve(ctx huma.Context, prefix *huma.PathBuffer) []error {
+	var errs []error
+	if b.Value != nil && b.Time != nil {
+		errs = append(errs, &huma.ErrorDetail{
+			Location: prefix.With("time"),
+			Message:  "value and time can't both be set",
+			Value:    *b.Time,
+		})
+	}
+	if b.Value == nil && b.Time == nil && b.Date == nil && b.BossName == nil && b.UserIDs == nil {
+		errs = append(errs, &huma.ErrorDetail{
+			Location: prefix.String(),
+			Message:  "at least one field must be set",
+		})
+	}
+	if b.UserIDs != nil && len(b.UserIDs) == 0 {
+		errs = append(errs, &huma.ErrorDetail{
+			Location: prefix.With("user_ids"),
+			Message:  "a record needs at least one team member",
+		})
+	}
+	if b.Date != nil && b.Date.After(time.Now()) {
+		errs = append(errs, &huma.ErrorDetail{
+			Location: prefix.With("date"),
+			Message:  "date can't be in the future",
+			Value:    *b.Date,
+		})
+	}
+	return errs
+}
+
+// Rows are validated one by one during import so a bad row is reported
+// instead of failing the whole request
+type ImportRecordRow struct {
+	Boss         string   `json:"boss"`
+	Value        string   `json:"value"          doc:"Raw value, or a time string such as 1:23.40 for time based bosses"`
+	Date         string   `json:"date,omitempty" doc:"RFC 3339 timestamp or YYYY-MM-DD, defaults to now"`
+	Participants []string `json:"participants"   doc:"Discord ids or RSNs"`
+}
+
+type ImportRecordsBody struct {
+	Rows []ImportRecordRow `json:"rows,omitempty" maxItems:"1000"`
+	CSV  string            `json:"csv,omitempty"  maxLength:"262144" doc:"CSV with the columns boss,value,date,participants, participants separated by semicolons"`
+}
+
+func (b ImportRecordsBody) Resolve(ctx huma.Context, prefix *huma.PathBuffer) []error {
+	if (len(b.Rows) == 0) == (b.CSV == "") {
+		return []error{&huma.ErrorDetail{
+			Location: prefix.With("rows"),
+			Message:  "exactly one of rows or csv must be set",
+		}}
+	}
+	return nil
+}
+
+var discordMessageLink = regexp.MustCompile(`^https://(ptb\.|canary\.)?discord(app)?\.com/channels/(\d+|@me)/\d+/\d+$`)
+
+type InputEvidence struct {
+	Kind        string           `json:"kind"         enum:"screenshot,video,message"`
+	URL         string           `json:"url"          minLength:"1" maxLength:"512" doc:"Screenshot or video URL, or a Discord message link"`
+	SubmittedBy DiscordSnowflake `json:"submitted_by"`
+}
+
+func (e InputEvidence) Resolve(ctx huma.Context, prefix *huma.PathBuffer) []error {
+	if e.Kind == "message" {
+		if !discordMessageLink.MatchString(e.URL) {
+			return []error{&huma.ErrorDetail{
+				Location: prefix.With("url"),
+				Message:  "must be a Discord message link",
+				Value:    e.URL,
+			}}
+		}
+		return nil
+	}
+
+	u, err := url.Parse(e.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return []error{&huma.ErrorDetail{
+			Location: prefix.With("url"),
+			Message:  "must be an http or https URL",
+			Value:    e.URL,
+		}}
+	}
+	return nil
+}
+
+type AddRecordEvidenceBody struct {
+	Evidence []InputEvidence `json:"evidence" minItems:"1" maxItems:"8"`
+}
+
+// EvidenceColumns splits evidence entries into the parallel arrays the
+// insert query unnests.
+func EvidenceColumns(evidence []InputEvidence) (kinds, urls, submittedBy []string) {
+	kinds = make([]string, len(evidence))
+	urls = make([]string, len(evidence))
+	submittedBy = make([]string, len(evidence))
+	for i, e := range evidence {
+		kinds[i] = e.Kind
+		urls[i] = e.URL
+		submittedBy[i] = string(e.SubmittedBy)
+	}
+	return kinds, urls, submittedBy
 }
 
 type InputTeammate struct {
@@ -59,6 +203,14 @@ type UpdateGuildBody struct {
 	ModChannelID  *DiscordSnowflake `json:"mod_channel_id,omitempty"`
 	PbUpdate      *PbUpdate         `json:"pb_update,omitempty"`
 	PositionCount *int              `json:"position_count,omitempty"`
+	TiePolicy     *string           `json:"tie_policy,omitempty" enum:"earliest,shared" doc:"How equal values are ranked, earliest gives the earlier achiever the higher position and shared gives tied records the same position"`
+	// Points are all-time by default, the leaderboard keeps showing
+	// all-time points unless seasonal points are enabled
+	SeasonalPoints *bool `json:"seasonal_points,omitempty" doc:"Show points earned during the current season on the leaderboard"`
+}
+
+type StartSeasonBody struct {
+	Name *string `json:"name,omitempty" minLength:"1" maxLength:"64" doc:"Name of the new season, defaults to Season N"`
 }
 
 type CreateGuildRankBody struct {
@@ -75,3 +227,260 @@ type UpdateGuildRankBody struct {
 	RoleID       *string `json:"role_id,omitempty"`
 	DisplayOrder *int    `json:"display_order,omitempty"`
 }
+
+// customNamePattern matches the guild prefix custom bosses and categories
+// are stored under, the catalog can't use it
+var customNamePattern = regexp.MustCompile(`^[0-9]+:`)
+
+// CustomName is the name a guild's custom boss or category is stored under,
+// the guild prefix keeps guilds from colliding with each other or the catalog
+func CustomName(guildID string, name string) string {
+	return guildID + ":" + name
+}
+
+type CreateCustomBossBody struct {
+	Name        string `json:"name"         minLength:"1" maxLength:"32" pattern:"^[a-z0-9_]+$" doc:"Boss name unique within the guild, e.g. zuk_no_prayer. It's stored as <guild_id>:<name>."`
+	DisplayName string `json:"display_name" minLength:"1" maxLength:"32"`
+	Category    string `json:"category"     minLength:"1" maxLength:"97" doc:"A global category or one of the guild's custom categories"`
+	Solo        bool   `json:"solo"`
+	ValueType   string `json:"value_type"   default:"time" minLength:"1" maxLength:"32"`
+}
+
+type UpdateCustomBossBody struct {
+	DisplayName *string `json:"display_name,omitempty" minLength:"1" maxLength:"32"`
+	Category    *string `json:"category,omitempty"     minLength:"1" maxLength:"97"`
+}
+
+type CreateCustomCategoryBody struct {
+	Name      string  `json:"name"                minLength:"1" maxLength:"64" doc:"Category name unique within the guild, it's stored as <guild_id>:<name>"`
+	Thumbnail *string `json:"thumbnail,omitempty" maxLength:"256"`
+	Order     int     `json:"order"               minimum:"0" maximum:"32767"`
+}
+
+type UpdateCustomCategoryBody struct {
+	Thumbnail *string `json:"thumbnail,omitempty" maxLength:"256"`
+	Order     *int    `json:"order,omitempty"     minimum:"0" maximum:"32767"`
+}
+
+type CreateBossBody struct {
+	Name        string     `json:"name"                  minLength:"1" maxLength:"32" pattern:"^[a-z0-9_]+$" doc:"Unique boss name, e.g. doom_of_mokhaiotl"`
+	DisplayName string     `json:"display_name"          minLength:"1" maxLength:"32"`
+	Category    string     `json:"category"              minLength:"1" maxLength:"64"`
+	Solo        bool       `json:"solo"`
+	ValueType   string     `json:"value_type"            default:"time" minLength:"1" maxLength:"32"`
+	ReleasedAt  *time.Time `json:"released_at,omitempty" doc:"Records dated before the release are rejected"`
+}
+
+// Solo and value type are fixed once created since existing records depend
+// on them
+type UpdateBossBody struct {
+	DisplayName *string    `json:"display_name,omitempty" minLength:"1" maxLength:"32"`
+	Category    *string    `json:"category,omitempty"     minLength:"1" maxLength:"64"`
+	ReleasedAt  *time.Time `json:"released_at,omitempty"`
+	Retired     *bool      `json:"retired,omitempty"      doc:"Retired bosses keep their records but take no new ones"`
+}
+
+type CreateCategoryBody struct {
+	Name      string  `json:"name"                minLength:"1" maxLength:"64"`
+	Thumbnail *string `json:"thumbnail,omitempty" maxLength:"256"`
+	Order     int     `json:"order"               minimum:"0" maximum:"32767"`
+}
+
+func (b CreateCategoryBody) Resolve(ctx huma.Context, prefix *huma.PathBuffer) []error {
+	if customNamePattern.MatchString(b.Name) {
+		return []error{&huma.ErrorDetail{
+			Location: prefix.With("name"),
+			Message:  "names starting with a guild id are reserved for custom categories",
+			Value:    b.Name,
+		}}
+	}
+	return nil
+}
+
+type UpdateCategoryBody struct {
+	Thumbnail *string `json:"thumbnail,omitempty" maxLength:"256"`
+	Order     *int    `json:"order,omitempty"     minimum:"0" maximum:"32767"`
+	Retired   *bool   `json:"retired,omitempty"   doc:"Only categories without active bosses can be retired"`
+}
+
+// Values are stored as whole steps, the step is what one stored unit is worth
+// in the display format: milliseconds for ticks_as_time, hundredths of a
+// percent for percentage and the plain number for integer. The display
+// format and step are fixed once created since existing records depend on
+// them.
+type CreateValueTypeBody struct {
+	Name           string  `json:"name"                minLength:"1" maxLength:"32" pattern:"^[a-z0-9_]+$"`
+	HigherIsBetter bool    `json:"higher_is_better"`
+	Unit           *string `json:"unit,omitempty"      minLength:"1" maxLength:"16" doc:"Shown after integer values, e.g. kc"`
+	DisplayFormat  string  `json:"display_format"      default:"integer" enum:"ticks_as_time,integer,percentage"`
+	MinValue       *int    `json:"min_value,omitempty" minimum:"-2147483648" maximum:"2147483647" doc:"Smallest stored value a record can have"`
+	MaxValue       *int    `json:"max_value,omitempty" minimum:"-2147483648" maximum:"2147483647" doc:"Largest stored value a record can have"`
+	Step           int     `json:"step"                default:"1" minimum:"1" maximum:"2147483647" doc:"What one stored unit is worth, e.g. 600 for a game tick"`
+}
+
+func (b CreateValueTypeBody) Resolve(ctx huma.Context, prefix *huma.PathBuffer) []error {
+	return resolveValueBounds(b.MinValue, b.MaxValue, prefix)
+}
+
+type UpdateValueTypeBody struct {
+	HigherIsBetter *bool   `json:"higher_is_better,omitempty"`
+	Unit           *string `json:"unit,omitempty"         minLength:"1" maxLength:"16"`
+	MinValue       *int    `json:"min_value,omitempty"    minimum:"-2147483648" maximum:"2147483647"`
+	MaxValue       *int    `json:"max_value,omitempty"    minimum:"-2147483648" maximum:"2147483647"`
+	ClearBounds    bool    `json:"clear_bounds,omitempty" doc:"Remove both bounds, can't be combined with min_value or max_value"`
+}
+
+func (b UpdateValueTypeBody) Resolve(ctx huma.Context, prefix *huma.PathBuffer) []error {
+	if b.ClearBounds && (b.MinValue != nil || b.MaxValue != nil) {
+		return []error{&huma.ErrorDetail{
+			Location: prefix.With("clear_bounds"),
+			Message:  "clear_bounds can't be combined with min_value or max_value",
+			Value:    b.ClearBounds,
+		}}
+	}
+	return resolveValueBounds(b.MinValue, b.MaxValue, prefix)
+}
+
+func resolveValueBounds(min *int, max *int, prefix *huma.PathBuffer) []error {
+	if min != nil && max != nil && *min > *max {
+		return []error{&huma.ErrorDetail{
+			Location: prefix.With("min_value"),
+			Message:  "min_value can't be above max_value",
+			Value:    *min,
+		}}
+	}
+	return nil
+}
+
+// A guild's own setting for one boss or category, fields left out are kept
+type GuildBossSetting struct {
+	Boss         string `json:"boss"                    minLength:"1" maxLength:"65"`
+	Enabled      *bool  `json:"enabled,omitempty"`
+	DisplayOrder *int   `json:"display_order,omitempty" minimum:"0" maximum:"32767"`
+	ResetOrder   bool   `json:"reset_order,omitempty"   doc:"Go back to the default ordering"`
+}
+
+type UpdateGuildBossesBody struct {
+	Bosses []GuildBossSetting `json:"bosses" minItems:"1" maxItems:"500"`
+}
+
+type GuildCategorySetting struct {
+	Category     string `json:"category"                minLength:"1" maxLength:"97"`
+	Enabled      *bool  `json:"enabled,omitempty"       doc:"Disabling a category also hides its bosses"`
+	DisplayOrder *int   `json:"display_order,omitempty" minimum:"0" maximum:"32767"`
+	ResetOrder   bool   `json:"reset_order,omitempty"   doc:"Go back to the category's default order"`
+}
+
+type UpdateGuildCategoriesBody struct {
+	Categories []GuildCategorySetting `json:"categories" minItems:"1" maxItems:"200"`
+}
+
+type MemberRoles struct {
+	UserID  DiscordSnowflake   `json:"user_id"`
+	RoleIDs []DiscordSnowflake `json:"role_ids"`
+}
+
+type RankAssignmentsBody struct {
+	Members []MemberRoles `json:"members"`
+}
+
+// UserFields - which optional DetailedUser fields to load
+type UserFields struct {
+	RSNs               bool
+	Records            bool
+	Events             bool
+	Achievements       bool
+	CombatAchievements bool
+	Rank               bool
+	Tier               bool
+}
+
+var AllUserFields = UserFields{true, true, true, true, true, true, true}
+
+// ParseUserFields turns include= values into UserFields, every field is
+// loaded when include is empty
+func ParseUserFields(include []string) UserFields {
+	if len(include) == 0 {
+		return AllUserFields
+	}
+	return UserFields{
+		RSNs:               slices.Contains(include, "rsns"),
+		Records:            slices.Contains(include, "records"),
+		Events:             slices.Contains(include, "events"),
+		Achievements:       slices.Contains(include, "achievements"),
+		CombatAchievements: slices.Contains(include, "combat_achievements"),
+		Rank:               slices.Contains(include, "rank"),
+		Tier:               slices.Contains(include, "tier"),
+	}
+}
+
+type APIKeyRole string
+
+const (
+	APIKeyRoleReadOnly APIKeyRole = "read_only"
+	APIKeyRoleBot      APIKeyRole = "bot"
+	APIKeyRoleAdmin    APIKeyRole = "admin"
+)
+
+type CreateAPIKeyBody struct {
+	Name string     `json:"name" minLength:"1" maxLength:"64"`
+	Role APIKeyRole `json:"role" enum:"read_only,bot,admin"`
+}
+
+type RevokePointsBody struct {
+	RevokedBy DiscordSnowflake `json:"revoked_by"`
+	Reason    string           `json:"reason"     minLength:"1" maxLength:"256"`
+}
+
+type ApproveRecordBody struct {
+	ReviewedBy DiscordSnowflake `json:"reviewed_by"`
+	Reason     *string          `json:"reason,omitempty" maxLength:"256"`
+}
+
+type RejectRecordBody struct {
+	ReviewedBy DiscordSnowflake `json:"reviewed_by"`
+	Reason     string           `json:"reason"      minLength:"1" maxLength:"256"`
+}
+
+type CreatePointSourceBody struct {
+	Source string `json:"source" minLength:"1" maxLength:"32" pattern:"^[a-z0-9_]+$" doc:"Point source key, lowercase letters, numbers and underscores"`
+	Name   string `json:"name"   minLength:"1" maxLength:"64"`
+	Points int    `json:"points"`
+}
+
+// Ledger sources that aren't backed by a point source row
+var reservedPointSources = []string{"custom", "revoke", "opening_balance"}
+
+func (b *CreatePointSourceBody) Resolve(ctx huma.Context, prefix *huma.PathBuffer) []error {
+	if slices.Contains(reservedPointSources, b.Source) {
+		return []error{&huma.ErrorDetail{
+			Location: prefix.With("source"),
+			Message:  "is reserved",
+			Value:    b.Source,
+		}}
+	}
+	return nil
+}
+
+type UpdatePointSourceBody struct {
+	Name   *string `json:"name,omitempty"   minLength:"1" maxLength:"64"`
+	Points *int    `json:"points,omitempty"`
+}
+
+type CreateMultiplierWindowBody struct {
+	Name       string    `json:"name"       minLength:"1" maxLength:"64"`
+	Multiplier int       `json:"multiplier" minimum:"1"   maximum:"10" doc:"Applied on top of the guild multiplier while the window is active"`
+	StartsAt   time.Time `json:"starts_at"`
+	EndsAt     time.Time `json:"ends_at"`
+}
+
+func (b *CreateMultiplierWindowBody) Resolve(ctx huma.Context, prefix *huma.PathBuffer) []error {
+	if !b.EndsAt.After(b.StartsAt) {
+		return []error{&huma.ErrorDetail{
+			Location: prefix.With("ends_at"),
+			Message:  "must be after starts_at",
+			Value:    b.EndsAt,
+		}}
+	}
+	return nil
+}
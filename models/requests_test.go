@@ -0,0 +1,102 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+func TestInputEvidenceResolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		evidence InputEvidence
+		valid    bool
+	}{
+		{name: "Screenshot URL", evidence: InputEvidence{Kind: "screenshot", URL: "https://i.imgur.com/abc.png"}, valid: true},
+		{name: "Video URL", evidence: InputEvidence{Kind: "video", URL: "https://youtu.be/dQw4w9WgXcQ"}, valid: true},
+		{name: "Message link", evidence: InputEvidence{Kind: "message", URL: "https://discord.com/channels/123/456/789"}, valid: true},
+		{name: "PTB message link", evidence: InputEvidence{Kind: "message", URL: "https://ptb.discord.com/channels/123/456/789"}, valid: true},
+		{name: "Message kind with other URL", evidence: InputEvidence{Kind: "message", URL: "https://i.imgur.com/abc.png"}, valid: false},
+		{name: "Channel link without message", evidence: InputEvidence{Kind: "message", URL: "https://discord.com/channels/123/456"}, valid: false},
+		{name: "Non http scheme", evidence: InputEvidence{Kind: "screenshot", URL: "ftp://example.com/a.png"}, valid: false},
+		{name: "Not a URL", evidence: InputEvidence{Kind: "video", URL: "my clip"}, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.evidence.Resolve(nil, &huma.PathBuffer{})
+			if tt.valid && len(errs) != 0 {
+				t.Errorf("Resolve(%q) returned %v, expected no errors", tt.evidence.URL, errs)
+			}
+			if !tt.valid && len(errs) == 0 {
+				t.Errorf("Resolve(%q) returned no errors, expected one", tt.evidence.URL)
+			}
+		})
+	}
+}
+
+func TestValueTypeBoundsResolve(t *testing.T) {
+	low, high := 1, 100
+	tests := []struct {
+		name  string
+		body  CreateValueTypeBody
+		valid bool
+	}{
+		{name: "No bounds", body: CreateValueTypeBody{}, valid: true},
+		{name: "Only a minimum", body: CreateValueTypeBody{MinValue: &high}, valid: true},
+		{name: "Ordered bounds", body: CreateValueTypeBody{MinValue: &low, MaxValue: &high}, valid: true},
+		{name: "Crossed bounds", body: CreateValueTypeBody{MinValue: &high, MaxValue: &low}, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.body.Resolve(nil, &huma.PathBuffer{})
+			if tt.valid != (len(errs) == 0) {
+				t.Errorf("Resolve() returned %v, expected valid: %t", errs, tt.valid)
+			}
+		})
+	}
+}
+
+func TestCategoryNameResolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		category string
+		valid    bool
+	}{
+		{name: "Catalog name", category: "Chambers of Xeric: CM", valid: true},
+		{name: "Custom name", category: CustomName("123456789012345678", "Challenges"), valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := CreateCategoryBody{Name: tt.category}.Resolve(nil, &huma.PathBuffer{})
+			if tt.valid != (len(errs) == 0) {
+				t.Errorf("Resolve(%q) returned %v, expected valid: %t", tt.category, errs, tt.valid)
+			}
+		})
+	}
+}
+
+func TestUpdateValueTypeResolve(t *testing.T) {
+	low, high := 1, 100
+	tests := []struct {
+		name  string
+		body  UpdateValueTypeBody
+		valid bool
+	}{
+		{name: "Clear bounds", body: UpdateValueTypeBody{ClearBounds: true}, valid: true},
+		{name: "New bounds", body: UpdateValueTypeBody{MinValue: &low, MaxValue: &high}, valid: true},
+		{name: "Clear bounds with a minimum", body: UpdateValueTypeBody{ClearBounds: true, MinValue: &low}, valid: false},
+		{name: "Clear bounds with a maximum", body: UpdateValueTypeBody{ClearBounds: true, MaxValue: &high}, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.body.Resolve(nil, &huma.PathBuffer{})
+			if tt.valid != (len(errs) == 0) {
+				t.Errorf("Resolve() returned %v, expected valid: %t", errs, tt.valid)
+			}
+		})
+	}
+}
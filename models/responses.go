@@ -8,17 +8,19 @@ import (
 )
 
 // DetailedUser - returned by user lookup and WOM competition endpoints
+// Fields other than user_id, guild_id and points are left out when they
+// weren't requested with include=
 type DetailedUser struct {
 	UserId             string                  `json:"user_id"`
 	GuildId            string                  `json:"guild_id"`
 	Points             int                     `json:"points"`
-	Rank               int64                   `json:"rank"`
+	Rank               int64                   `json:"rank,omitzero"`
 	Tier               *UserTier               `json:"tier,omitempty"`
-	RSNs               []UserRsn               `json:"rsns"`
-	Records            []UserRecord            `json:"records"`
-	Events             []UserEvent             `json:"events"`
-	Achievements       []UserAchievement       `json:"achievements"`
-	CombatAchievements []UserCombatAchievement `json:"combat_achievements"`
+	RSNs               []UserRsn               `json:"rsns,omitzero"`
+	Records            []UserRecord            `json:"records,omitzero"`
+	Events             []UserEvent             `json:"events,omitzero"`
+	Achievements       []UserAchievement       `json:"achievements,omitzero"`
+	CombatAchievements []UserCombatAchievement `json:"combat_achievements,omitzero"`
 }
 
 // UserTier - the user's current rank tier based on points
@@ -37,7 +39,7 @@ type UserRsn struct {
 	WomId string `json:"wom_id"`
 }
 
-func UserRsnsFromRows(rows []database.GetUserRsnsRow) []UserRsn {
+func UserRsnsFromRows(rows []database.GetUsersRsnsRow) []UserRsn {
 	result := make([]UserRsn, len(rows))
 	for i := range rows {
 		result[i] = UserRsn{
@@ -55,7 +57,7 @@ type UserAchievement struct {
 	Order       int16  `json:"order"`
 }
 
-func UserAchievementsFromRows(rows []database.GetUserAchievementsRow) []UserAchievement {
+func UserAchievementsFromRows(rows []database.GetUsersAchievementsRow) []UserAchievement {
 	result := make([]UserAchievement, len(rows))
 	for i := range rows {
 		result[i] = UserAchievement{
@@ -76,7 +78,7 @@ type UserEvent struct {
 	Solo           bool   `json:"solo"`
 }
 
-func UserEventFromRows(rows []database.GetUserEventsRow) []UserEvent {
+func UserEventFromRows(rows []database.GetUsersEventsRow) []UserEvent {
 	result := make([]UserEvent, len(rows))
 	for i := range rows {
 		result[i] = UserEvent{
@@ -97,15 +99,37 @@ type RecordTeammate struct {
 }
 
 type UserRecord struct {
-	Id          int32            `json:"record_id"`
-	BossName    string           `json:"boss_name"`
-	DisplayName string           `json:"display_name"`
-	Category    string           `json:"category"`
-	Solo        bool             `json:"solo"`
-	ValueType   string           `json:"value_type"`
-	Date        time.Time        `json:"date"`
-	Value       int32            `json:"value"`
-	Teammates   []RecordTeammate `json:"team"`
+	Id           int32            `json:"record_id"`
+	BossName     string           `json:"boss_name"`
+	DisplayName  string           `json:"display_name"`
+	Category     string           `json:"category"`
+	Solo         bool             `json:"solo"`
+	ValueType    string           `json:"value_type"`
+	Date         time.Time        `json:"date"`
+	Value        int32            `json:"value"`
+	DisplayValue string           `json:"display_value"`
+	Position     *int64           `json:"position,omitempty" doc:"Position on the boss ranking, absent when the record isn't ranked"`
+	Teammates    []RecordTeammate `json:"team"`
+	Evidence     []RecordEvidence `json:"evidence"`
+}
+
+type RecordEvidence struct {
+	EvidenceID  int32  `json:"evidence_id"`
+	RecordID    int32  `json:"record_id"`
+	Kind        string `json:"kind"`
+	URL         string `json:"url"`
+	SubmittedBy string `json:"submitted_by"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// RecordEvidenceFromJSON decodes a json_agg of record_evidence rows
+func RecordEvidenceFromJSON(b []byte) []RecordEvidence {
+	evidence := []RecordEvidence{}
+	json.Unmarshal(b, &evidence)
+	if evidence == nil {
+		return []RecordEvidence{}
+	}
+	return evidence
 }
 
 func UserRecordsFromRows(rows []database.GetUserRecordsRow) []UserRecord {
@@ -134,6 +158,10 @@ func UserRecordsFromRows(rows []database.GetUserRecordsRow) []UserRecord {
 				Date:        rows[i].Date.Time,
 				Value:       rows[i].Value,
 				Teammates:   make([]RecordTeammate, 0),
+				Evidence:    RecordEvidenceFromJSON(rows[i].Evidence),
+			}
+			if rows[i].Position.Valid {
+				r.Position = &rows[i].Position.Int64
 			}
 		}
 		r.Teammates = append(r.Teammates, RecordTeammate{
@@ -150,11 +178,11 @@ type UserCombatAchievement struct {
 	Name string `json:"name"`
 }
 
-func UserCombatAchievementsFromRows(rows []string) []UserCombatAchievement {
+func UserCombatAchievementsFromRows(rows []database.GetUsersCombatAchievementsRow) []UserCombatAchievement {
 	result := make([]UserCombatAchievement, len(rows))
 	for i := range rows {
 		result[i] = UserCombatAchievement{
-			Name: rows[i],
+			Name: rows[i].CombatAchievementName,
 		}
 	}
 	return result
@@ -162,11 +190,347 @@ func UserCombatAchievementsFromRows(rows []string) []UserCombatAchievement {
 
 // Record creation response
 type RecordResponse struct {
-	BossName string `json:"boss_name"`
-	Value    int    `json:"value"`
-	OldValue int    `json:"value_old"`
-	RecordID int    `json:"record_id"`
-	Position *int   `json:"position,omitempty"`
+	BossName        string `json:"boss_name"`
+	Value           int    `json:"value"`
+	DisplayValue    string `json:"display_value"`
+	OldValue        int    `json:"value_old"`
+	DisplayOldValue string `json:"display_value_old"`
+	RecordID        int    `json:"record_id"`
+	Position        *int   `json:"position,omitempty"`
+	Status          string `json:"status" enum:"pending,approved,rejected"`
+
+	// Top of the boss ranking before and after the record was placed
+	TopBefore []RankedRecord `json:"top_before,omitempty"`
+	TopAfter  []RankedRecord `json:"top_after,omitempty"`
+	Moved     []RecordMove   `json:"moved,omitempty"`
+	Dropped   []RecordMove   `json:"dropped,omitempty"`
+}
+
+func (r *RecordResponse) ApplyPlacement(p RecordPlacement) {
+	if p.Position > 0 {
+		r.Position = &p.Position
+	}
+	r.OldValue = p.OldValue
+	r.TopBefore = p.Before
+	r.TopAfter = p.After
+	r.Moved = p.Moved
+	r.Dropped = p.Dropped
+}
+
+// Record edit response, previous position is where the record stood before
+// the edit so announcements can be corrected
+type UpdateRecordResponse struct {
+	RecordResponse
+	PreviousBossName string `json:"boss_name_previous"`
+	PreviousPosition *int   `json:"position_previous,omitempty"`
+}
+
+// Bulk import report, rows are numbered from 1 in submission order
+type ImportRecordResult struct {
+	Row      int      `json:"row"`
+	Status   string   `json:"status" enum:"created,duplicate,rejected"`
+	Reason   string   `json:"reason,omitempty"`
+	RecordID *int32   `json:"record_id,omitempty"`
+	BossName string   `json:"boss_name"`
+	Value    int      `json:"value,omitempty"`
+	UserIDs  []string `json:"user_ids,omitempty"`
+}
+
+type ImportRecordsResponse struct {
+	DryRun       bool                 `json:"dry_run"`
+	RecordStatus string               `json:"record_status" enum:"approved,pending" doc:"Status of the created records, guilds with a mod channel review them first"`
+	Created      int                  `json:"created"`
+	Duplicates   int                  `json:"duplicates"`
+	Rejected     int                  `json:"rejected"`
+	Rows         []ImportRecordResult `json:"rows"`
+}
+
+// Full ranking for a single boss
+type BossRankingEntry struct {
+	Position     int64     `json:"position"`
+	RecordID     int32     `json:"record_id"`
+	Value        int32     `json:"value"`
+	DisplayValue string    `json:"display_value"`
+	GapToFirst   int32     `json:"gap_to_first"`
+	Date         time.Time `json:"date"`
+	UserIDs      []string  `json:"user_ids"`
+}
+
+type BossRanking struct {
+	BossName    string             `json:"boss_name"`
+	DisplayName string             `json:"display_name"`
+	Solo        bool               `json:"solo"`
+	ValueType   string             `json:"value_type"`
+	Entries     []BossRankingEntry `json:"entries"`
+}
+
+func BossRankingFromRows(boss database.GetBossInfoRow, rows []database.GetBossRankingRow) BossRanking {
+	ranking := BossRanking{
+		BossName:    boss.Name,
+		DisplayName: boss.DisplayName,
+		Solo:        boss.Solo,
+		ValueType:   boss.ValueType,
+		Entries:     make([]BossRankingEntry, len(rows)),
+	}
+	for i, row := range rows {
+		ranking.Entries[i] = BossRankingEntry{
+			Position:   row.Position,
+			RecordID:   row.RecordID,
+			Value:      row.Value,
+			GapToFirst: row.Gap,
+			Date:       row.Date.Time,
+			UserIDs:    row.UserIds,
+		}
+	}
+	return ranking
+}
+
+// Single record with its review state and evidence
+type RecordDetail struct {
+	UserRecord
+	Status       string  `json:"status" enum:"pending,approved,rejected"`
+	SubmittedBy  *string `json:"submitted_by,omitempty"`
+	ReviewedBy   *string `json:"reviewed_by,omitempty"`
+	ReviewReason *string `json:"review_reason,omitempty"`
+}
+
+func RecordDetailFromRows(rows []database.GetRecordRow) RecordDetail {
+	if len(rows) == 0 {
+		return RecordDetail{}
+	}
+
+	first := rows[0]
+	d := RecordDetail{
+		UserRecord: UserRecord{
+			Id:          first.RecordID,
+			BossName:    first.BossName,
+			DisplayName: first.DisplayName,
+			Category:    first.Category,
+			Solo:        first.Solo,
+			ValueType:   first.ValueType,
+			Date:        first.Date.Time,
+			Value:       first.Value,
+			Teammates:   make([]RecordTeammate, len(rows)),
+			Evidence:    RecordEvidenceFromJSON(first.Evidence),
+		},
+		Status: first.Status,
+	}
+	for i, row := range rows {
+		d.Teammates[i] = RecordTeammate{UserID: row.UserID, GuildID: row.GuildID}
+	}
+	if first.Position.Valid {
+		d.Position = &first.Position.Int64
+	}
+	if first.SubmittedBy.Valid {
+		d.SubmittedBy = &first.SubmittedBy.String
+	}
+	if first.ReviewedBy.Valid {
+		d.ReviewedBy = &first.ReviewedBy.String
+	}
+	if first.ReviewReason.Valid {
+		d.ReviewReason = &first.ReviewReason.String
+	}
+	return d
+}
+
+// Record moderation queue
+type PendingRecord struct {
+	RecordID     int32            `json:"record_id"`
+	BossName     string           `json:"boss_name"`
+	ValueType    string           `json:"value_type"`
+	Value        int32            `json:"value"`
+	DisplayValue string           `json:"display_value"`
+	Date         time.Time        `json:"date"`
+	SubmittedBy  *string          `json:"submitted_by,omitempty"`
+	Evidence     []RecordEvidence `json:"evidence"`
+	UserIDs      []string         `json:"user_ids"`
+}
+
+type PendingRecordsResponse struct {
+	ModChannelID *string         `json:"mod_channel_id"`
+	Records      []PendingRecord `json:"records"`
+}
+
+func PendingRecordsFromRows(rows []database.GetPendingRecordsRow) []PendingRecord {
+	result := make([]PendingRecord, 0)
+	for _, row := range rows {
+		n := len(result)
+		if n > 0 && result[n-1].RecordID == row.RecordID {
+			result[n-1].UserIDs = append(result[n-1].UserIDs, row.UserID)
+			continue
+		}
+
+		r := PendingRecord{
+			RecordID:  row.RecordID,
+			BossName:  row.BossName,
+			ValueType: row.ValueType,
+			Value:     row.Value,
+			Date:      row.Date.Time,
+			Evidence:  RecordEvidenceFromJSON(row.Evidence),
+			UserIDs:   []string{row.UserID},
+		}
+		if row.SubmittedBy.Valid {
+			r.SubmittedBy = &row.SubmittedBy.String
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+// Soft deleted record that can still be restored until PurgeAt
+type DeletedRecord struct {
+	RecordID     int32     `json:"record_id"`
+	BossName     string    `json:"boss_name"`
+	ValueType    string    `json:"value_type"`
+	Value        int32     `json:"value"`
+	DisplayValue string    `json:"display_value"`
+	Date         time.Time `json:"date"`
+	Status       string    `json:"status" enum:"pending,approved,rejected"`
+	DeletedAt    time.Time `json:"deleted_at"`
+	DeletedBy    *string   `json:"deleted_by,omitempty"`
+	PurgeAt      time.Time `json:"purge_at"`
+	UserIDs      []string  `json:"user_ids"`
+}
+
+type DeletedRecordsResponse struct {
+	Records []DeletedRecord `json:"records"`
+}
+
+func DeletedRecordsFromRows(rows []database.GetDeletedRecordsRow, retention time.Duration) []DeletedRecord {
+	result := make([]DeletedRecord, len(rows))
+	for i, row := range rows {
+		result[i] = DeletedRecord{
+			RecordID:  row.RecordID,
+			BossName:  row.BossName,
+			ValueType: row.ValueType,
+			Value:     row.Value,
+			Date:      row.Date.Time,
+			Status:    row.Status,
+			DeletedAt: row.DeletedAt.Time,
+			PurgeAt:   row.DeletedAt.Time.Add(retention),
+			UserIDs:   row.UserIds,
+		}
+		if row.DeletedBy.Valid {
+			result[i].DeletedBy = &row.DeletedBy.String
+		}
+	}
+	return result
+}
+
+// A boss as configured by a guild, DisplayOrder is nil when the guild
+// keeps the default ordering
+type GuildBossSettings struct {
+	Name         string `json:"name"`
+	DisplayName  string `json:"display_name"`
+	Category     string `json:"category"`
+	Solo         bool   `json:"solo"`
+	ValueType    string `json:"value_type"`
+	Custom       bool   `json:"custom"`
+	Retired      bool   `json:"retired" doc:"Retired bosses keep their records but take no new ones"`
+	Enabled      bool   `json:"enabled"`
+	DisplayOrder *int16 `json:"display_order"`
+}
+
+func GuildBossSettingsFromRows(rows []database.GetGuildBossesRow) []GuildBossSettings {
+	result := make([]GuildBossSettings, len(rows))
+	for i, row := range rows {
+		result[i] = GuildBossSettings{
+			Name:        row.Name,
+			DisplayName: row.DisplayName,
+			Category:    row.Category,
+			Solo:        row.Solo,
+			ValueType:   row.ValueType,
+			Custom:      row.Custom,
+			Retired:     row.Retired,
+			Enabled:     row.Enabled,
+		}
+		if row.DisplayOrder.Valid {
+			result[i].DisplayOrder = &row.DisplayOrder.Int16
+		}
+	}
+	return result
+}
+
+type GuildCategorySettings struct {
+	Name         string  `json:"name"`
+	Thumbnail    *string `json:"thumbnail"`
+	Order        int16   `json:"order" doc:"Default order of the category"`
+	Custom       bool    `json:"custom"`
+	Enabled      bool    `json:"enabled"`
+	DisplayOrder *int16  `json:"display_order"`
+}
+
+func GuildCategorySettingsFromRows(rows []database.GetGuildCategoriesRow) []GuildCategorySettings {
+	result := make([]GuildCategorySettings, len(rows))
+	for i, row := range rows {
+		result[i] = GuildCategorySettings{
+			Name:    row.Name,
+			Order:   row.Order,
+			Custom:  row.Custom,
+			Enabled: row.Enabled,
+		}
+		if row.Thumbnail.Valid {
+			result[i].Thumbnail = &row.Thumbnail.String
+		}
+		if row.DisplayOrder.Valid {
+			result[i].DisplayOrder = &row.DisplayOrder.Int16
+		}
+	}
+	return result
+}
+
+type SyncGuildBossesResponse struct {
+	BossesAdded     int64 `json:"bosses_added"`
+	CategoriesAdded int64 `json:"categories_added"`
+}
+
+// A guild season, EndedAt is nil for the current season
+type Season struct {
+	SeasonID    int32      `json:"season_id"`
+	Name        string     `json:"name"`
+	StartedAt   time.Time  `json:"started_at"`
+	EndedAt     *time.Time `json:"ended_at"`
+	Current     bool       `json:"current"`
+	RecordCount int64      `json:"record_count"`
+}
+
+func SeasonsFromRows(rows []database.GetGuildSeasonsRow) []Season {
+	result := make([]Season, len(rows))
+	for i, row := range rows {
+		result[i] = Season{
+			SeasonID:    row.SeasonID,
+			Name:        row.Name,
+			StartedAt:   row.StartedAt.Time,
+			Current:     !row.EndedAt.Valid,
+			RecordCount: row.RecordCount,
+		}
+		if row.EndedAt.Valid {
+			result[i].EndedAt = &row.EndedAt.Time
+		}
+	}
+	return result
+}
+
+type StartSeasonResponse struct {
+	Closed Season `json:"closed" doc:"The season that was closed, its records stay readable"`
+	Opened Season `json:"opened"`
+}
+
+func StartSeasonResponseFromRows(closed database.CloseSeasonRow, opened database.CreateSeasonRow) StartSeasonResponse {
+	return StartSeasonResponse{
+		Closed: Season{
+			SeasonID:  closed.SeasonID,
+			Name:      closed.Name,
+			StartedAt: closed.StartedAt.Time,
+			EndedAt:   &closed.EndedAt.Time,
+		},
+		Opened: Season{
+			SeasonID:  opened.SeasonID,
+			Name:      opened.Name,
+			StartedAt: opened.StartedAt.Time,
+			Current:   true,
+		},
+	}
 }
 
 // Event detail response
@@ -188,12 +552,14 @@ type GuildTeammate struct {
 }
 
 type GuildRecord struct {
-	RecordID int32  `json:"record_id"`
-	Value    int32  `json:"value"`
-	BossName string `json:"boss_name"`
-	Date     string `json:"date"`
-	GuildID  string `json:"guild_id"`
-	Position int64  `json:"position"`
+	RecordID     int32  `json:"record_id"`
+	Value        int32  `json:"value"`
+	DisplayValue string `json:"display_value"`
+	BossName     string `json:"boss_name"`
+	ValueType    string `json:"value_type"`
+	Date         string `json:"date"`
+	GuildID      string `json:"guild_id"`
+	Position     int64  `json:"position"`
 }
 
 type GuildBoss struct {
@@ -202,6 +568,7 @@ type GuildBoss struct {
 	Category    string `json:"category"`
 	Solo        bool   `json:"solo"`
 	ValueType   string `json:"value_type"`
+	Retired     bool   `json:"retired"`
 }
 
 type GuildCategory struct {
@@ -211,15 +578,17 @@ type GuildCategory struct {
 }
 
 type GuildBossEntry struct {
-	Boss     string `json:"boss"`
-	GuildID  string `json:"guild_id"`
-	Category string `json:"category"`
+	Boss         string `json:"boss"`
+	GuildID      string `json:"guild_id"`
+	Category     string `json:"category"`
+	DisplayOrder *int16 `json:"display_order"`
 }
 
 type GuildCategoryEntry struct {
-	GuildID   string `json:"guild_id"`
-	Category  string `json:"category"`
-	MessageID string `json:"message_id"`
+	GuildID      string `json:"guild_id"`
+	Category     string `json:"category"`
+	MessageID    string `json:"message_id"`
+	DisplayOrder *int16 `json:"display_order"`
 }
 
 type GuildRankResponse struct {
@@ -233,6 +602,7 @@ type GuildRankResponse struct {
 type GuildDetails struct {
 	Teammates       []GuildTeammate      `json:"teammates,omitempty"`
 	Records         []GuildRecord        `json:"records,omitempty"`
+	Evidence        []RecordEvidence     `json:"evidence,omitempty"`
 	Bosses          []GuildBoss          `json:"bosses,omitempty"`
 	Categories      []GuildCategory      `json:"categories,omitempty"`
 	GuildBosses     []GuildBossEntry     `json:"guild_bosses,omitempty"`
@@ -240,13 +610,16 @@ type GuildDetails struct {
 }
 
 type GuildResponse struct {
-	GuildID       string  `json:"guild_id"`
-	Multiplier    int32   `json:"multiplier"`
-	PbChannelID   *string `json:"pb_channel_id"`
-	ModChannelID  *string `json:"mod_channel_id"`
-	PositionCount int16   `json:"position_count"`
-	UserCount     int64   `json:"user_count"`
-	RecordCount   int64   `json:"record_count"`
+	GuildID        string  `json:"guild_id"`
+	Multiplier     int32   `json:"multiplier"`
+	PbChannelID    *string `json:"pb_channel_id"`
+	ModChannelID   *string `json:"mod_channel_id"`
+	PositionCount  int16   `json:"position_count"`
+	TiePolicy      string  `json:"tie_policy" enum:"earliest,shared"`
+	SeasonalPoints bool    `json:"seasonal_points"`
+	SeasonID       int32   `json:"season_id" doc:"Season the records belong to"`
+	UserCount      int64   `json:"user_count"`
+	RecordCount    int64   `json:"record_count"`
 
 	GuildDetails
 }
@@ -263,13 +636,16 @@ func GuildResponseFromRow(row database.GetGuildRow) GuildResponse {
 	}
 
 	return GuildResponse{
-		GuildID:       row.GuildID,
-		Multiplier:    row.Multiplier,
-		PbChannelID:   pbChannelID,
-		ModChannelID:  modChannelID,
-		PositionCount: row.PositionCount,
-		UserCount:     row.UserCount,
-		RecordCount:   row.RecordCount,
+		GuildID:        row.GuildID,
+		Multiplier:     row.Multiplier,
+		PbChannelID:    pbChannelID,
+		ModChannelID:   modChannelID,
+		PositionCount:  row.PositionCount,
+		TiePolicy:      row.TiePolicy,
+		SeasonalPoints: row.SeasonalPoints,
+		SeasonID:       row.SeasonID,
+		UserCount:      row.UserCount,
+		RecordCount:    row.RecordCount,
 	}
 }
 
@@ -285,16 +661,20 @@ func GuildResponseFromDetailedRow(row database.GetDetailedGuildRow) GuildRespons
 	}
 
 	g := GuildResponse{
-		GuildID:       row.GuildID,
-		Multiplier:    row.Multiplier,
-		PbChannelID:   pbChannelID,
-		ModChannelID:  modChannelID,
-		PositionCount: row.PositionCount,
-		UserCount:     row.UserCount,
-		RecordCount:   row.RecordCount,
+		GuildID:        row.GuildID,
+		Multiplier:     row.Multiplier,
+		PbChannelID:    pbChannelID,
+		ModChannelID:   modChannelID,
+		PositionCount:  row.PositionCount,
+		TiePolicy:      row.TiePolicy,
+		SeasonalPoints: row.SeasonalPoints,
+		SeasonID:       row.SeasonID,
+		UserCount:      row.UserCount,
+		RecordCount:    row.RecordCount,
 		GuildDetails: GuildDetails{
 			Teammates:       []GuildTeammate{},
 			Records:         []GuildRecord{},
+			Evidence:        []RecordEvidence{},
 			Bosses:          []GuildBoss{},
 			Categories:      []GuildCategory{},
 			GuildBosses:     []GuildBossEntry{},
@@ -304,6 +684,7 @@ func GuildResponseFromDetailedRow(row database.GetDetailedGuildRow) GuildRespons
 
 	json.Unmarshal(row.Teammates, &g.Teammates)
 	json.Unmarshal(row.Records, &g.Records)
+	json.Unmarshal(row.Evidence, &g.Evidence)
 	json.Unmarshal(row.Bosses, &g.Bosses)
 	json.Unmarshal(row.Categories, &g.Categories)
 	json.Unmarshal(row.GuildBosses, &g.GuildBosses)
@@ -352,3 +733,119 @@ func GuildRanksFromRows(rows []database.GetGuildRanksRow) []GuildRankResponse {
 	}
 	return result
 }
+
+// APIKeyResponse - returned by the API key endpoints, Key is only set when
+// a key is issued or rotated and can't be recovered afterwards
+type APIKeyResponse struct {
+	KeyID     int32      `json:"key_id"`
+	GuildID   string     `json:"guild_id"`
+	Name      string     `json:"name"`
+	Role      string     `json:"role"`
+	Prefix    string     `json:"prefix"`
+	CreatedAt time.Time  `json:"created_at"`
+	RotatedAt *time.Time `json:"rotated_at,omitempty"`
+	Key       string     `json:"key,omitempty"`
+}
+
+func APIKeyFromRow(row database.GetGuildAPIKeysRow) APIKeyResponse {
+	k := APIKeyResponse{
+		KeyID:     row.KeyID,
+		GuildID:   row.GuildID,
+		Name:      row.Name,
+		Role:      row.Role,
+		Prefix:    row.KeyPrefix,
+		CreatedAt: row.CreatedAt.Time,
+	}
+	if row.RotatedAt.Valid {
+		k.RotatedAt = &row.RotatedAt.Time
+	}
+	return k
+}
+
+func APIKeysFromRows(rows []database.GetGuildAPIKeysRow) []APIKeyResponse {
+	result := make([]APIKeyResponse, len(rows))
+	for i, row := range rows {
+		result[i] = APIKeyFromRow(row)
+	}
+	return result
+}
+
+// PointTransaction - a single entry in a user's points ledger
+type PointTransaction struct {
+	TransactionID         int32      `json:"transaction_id"`
+	BatchID               int32      `json:"batch_id"`
+	Source                string     `json:"source"`
+	Delta                 int32      `json:"delta"`
+	Multiplier            int32      `json:"multiplier"`
+	RecordID              *int32     `json:"record_id,omitempty"`
+	EventID               *string    `json:"event_id,omitempty"`
+	CombatAchievement     *string    `json:"combat_achievement,omitempty"`
+	ReversesTransactionID *int32     `json:"reverses_transaction_id,omitempty"`
+	CreatedAt             time.Time  `json:"created_at"`
+	RevokedAt             *time.Time `json:"revoked_at,omitempty"`
+	RevokedBy             *string    `json:"revoked_by,omitempty"`
+	RevokeReason          *string    `json:"revoke_reason,omitempty"`
+}
+
+func PointTransactionsFromRows(rows []database.GetUserPointTransactionsRow) []PointTransaction {
+	result := make([]PointTransaction, len(rows))
+	for i, row := range rows {
+		t := PointTransaction{
+			TransactionID: row.TransactionID,
+			BatchID:       row.BatchID,
+			Source:        row.Source,
+			Delta:         row.Delta,
+			Multiplier:    row.Multiplier,
+			CreatedAt:     row.CreatedAt.Time,
+		}
+		if row.RecordID.Valid {
+			t.RecordID = &row.RecordID.Int32
+		}
+		if row.EventID.Valid {
+			t.EventID = &row.EventID.String
+		}
+		if row.CombatAchievement.Valid {
+			t.CombatAchievement = &row.CombatAchievement.String
+		}
+		if row.ReversesTransactionID.Valid {
+			t.ReversesTransactionID = &row.ReversesTransactionID.Int32
+		}
+		if row.RevokedAt.Valid {
+			t.RevokedAt = &row.RevokedAt.Time
+		}
+		if row.RevokedBy.Valid {
+			t.RevokedBy = &row.RevokedBy.String
+		}
+		if row.RevokeReason.Valid {
+			t.RevokeReason = &row.RevokeReason.String
+		}
+		result[i] = t
+	}
+	return result
+}
+
+type MultiplierWindow struct {
+	WindowID   int32     `json:"window_id"`
+	Name       string    `json:"name"`
+	Multiplier int32     `json:"multiplier"`
+	StartsAt   time.Time `json:"starts_at"`
+	EndsAt     time.Time `json:"ends_at"`
+}
+
+func MultiplierWindowFromRow(row database.GetGuildMultiplierWindowsRow) MultiplierWindow {
+	return MultiplierWindow{
+		WindowID:   row.WindowID,
+		Name:       row.Name,
+		Multiplier: row.Multiplier,
+		StartsAt:   row.StartsAt.Time,
+		EndsAt:     row.EndsAt.Time,
+	}
+}
+
+func MultiplierWindowsFromRows(rows []database.GetGuildMultiplierWindowsRow) []MultiplierWindow {
+	result := make([]MultiplierWindow, len(rows))
+	for i, row := range rows {
+		result[i] = MultiplierWindowFromRow(row)
+	}
+	return result
+}
@@ -0,0 +1,43 @@
+package routes
+
+import (
+	"net/http"
+
+	"tectonic-api/handlers"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+func RegisterAPIKeyRoutes(api huma.API, s *handlers.Server) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-api-keys",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/guilds/{guild_id}/keys",
+		Summary:     "List a guild's active API keys",
+		Tags:        []string{"API Key"},
+	}, s.GetAPIKeys)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "create-api-key",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/guilds/{guild_id}/keys",
+		Summary:     "Issue a new API key for a guild",
+		Tags:        []string{"API Key"},
+	}, s.CreateAPIKey)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "rotate-api-key",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/guilds/{guild_id}/keys/{key_id}/rotate",
+		Summary:     "Replace an API key's secret, keeping its name and role",
+		Tags:        []string{"API Key"},
+	}, s.RotateAPIKey)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "revoke-api-key",
+		Method:      http.MethodDelete,
+		Path:        "/api/v1/guilds/{guild_id}/keys/{key_id}",
+		Summary:     "Revoke an API key",
+		Tags:        []string{"API Key"},
+	}, s.RevokeAPIKey)
+}
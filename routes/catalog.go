@@ -0,0 +1,61 @@
+package routes
+
+import (
+	"net/http"
+
+	"tectonic-api/handlers"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// Catalog writes sit outside the guild routes, so only the master key can
+// use them
+func RegisterCatalogRoutes(api huma.API, s *handlers.Server) {
+	huma.Register(api, huma.Operation{
+		OperationID: "create-boss",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/bosses",
+		Summary:     "Add a global boss to every guild",
+		Tags:        []string{"Catalog"},
+	}, s.CreateBoss)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "update-boss",
+		Method:      http.MethodPut,
+		Path:        "/api/v1/bosses/{boss}",
+		Summary:     "Rename, re-categorize or retire a global boss",
+		Tags:        []string{"Catalog"},
+	}, s.UpdateBoss)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "create-category",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/categories",
+		Summary:     "Add a global category to every guild",
+		Tags:        []string{"Catalog"},
+	}, s.CreateCategory)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "update-category",
+		Method:      http.MethodPut,
+		Path:        "/api/v1/categories/{category}",
+		Summary:     "Update or retire a global category",
+		Tags:        []string{"Catalog"},
+	}, s.UpdateCategory)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "create-value-type",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/value-types",
+		Summary:     "Add a value type",
+		Tags:        []string{"Catalog"},
+	}, s.CreateValueType)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "update-value-type",
+		Method:      http.MethodPut,
+		Path:        "/api/v1/value-types/{value_type}",
+		Summary:     "Update a value type",
+		Tags:        []string{"Catalog"},
+	}, s.UpdateValueType)
+}
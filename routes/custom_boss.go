@@ -0,0 +1,75 @@
+package routes
+
+import (
+	"net/http"
+
+	"tectonic-api/handlers"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+func RegisterCustomBossRoutes(api huma.API, s *handlers.Server) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-custom-bosses",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/guilds/{guild_id}/bosses/custom",
+		Summary:     "Get the guild's custom bosses",
+		Tags:        []string{"Custom Boss"},
+	}, s.GetCustomBosses)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "create-custom-boss",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/guilds/{guild_id}/bosses/custom",
+		Summary:     "Create a custom boss for the guild",
+		Tags:        []string{"Custom Boss"},
+	}, s.CreateCustomBoss)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "update-custom-boss",
+		Method:      http.MethodPut,
+		Path:        "/api/v1/guilds/{guild_id}/bosses/custom/{boss}",
+		Summary:     "Update a custom boss",
+		Tags:        []string{"Custom Boss"},
+	}, s.UpdateCustomBoss)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "delete-custom-boss",
+		Method:      http.MethodDelete,
+		Path:        "/api/v1/guilds/{guild_id}/bosses/custom/{boss}",
+		Summary:     "Delete a custom boss without records",
+		Tags:        []string{"Custom Boss"},
+	}, s.DeleteCustomBoss)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-custom-categories",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/guilds/{guild_id}/categories/custom",
+		Summary:     "Get the guild's custom categories",
+		Tags:        []string{"Custom Boss"},
+	}, s.GetCustomCategories)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "create-custom-category",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/guilds/{guild_id}/categories/custom",
+		Summary:     "Create a custom category for the guild",
+		Tags:        []string{"Custom Boss"},
+	}, s.CreateCustomCategory)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "update-custom-category",
+		Method:      http.MethodPut,
+		Path:        "/api/v1/guilds/{guild_id}/categories/custom/{category}",
+		Summary:     "Update a custom category",
+		Tags:        []string{"Custom Boss"},
+	}, s.UpdateCustomCategory)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "delete-custom-category",
+		Method:      http.MethodDelete,
+		Path:        "/api/v1/guilds/{guild_id}/categories/custom/{category}",
+		Summary:     "Delete a custom category without bosses",
+		Tags:        []string{"Custom Boss"},
+	}, s.DeleteCustomCategory)
+}
@@ -0,0 +1,59 @@
+package routes
+
+import (
+	"net/http"
+
+	"tectonic-api/handlers"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+func RegisterGuildBossRoutes(api huma.API, s *handlers.Server) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-guild-bosses",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/guilds/{guild_id}/bosses",
+		Summary:     "Get the guild's bosses with their enabled flag and ordering",
+		Tags:        []string{"Guild Boss"},
+	}, s.GetGuildBosses)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "update-guild-bosses",
+		Method:      http.MethodPut,
+		Path:        "/api/v1/guilds/{guild_id}/bosses",
+		Summary:     "Enable, disable or reorder the guild's bosses",
+		Tags:        []string{"Guild Boss"},
+	}, s.UpdateGuildBosses)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "sync-guild-bosses",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/guilds/{guild_id}/bosses/sync",
+		Summary:     "Add newly released global bosses and categories to the guild",
+		Tags:        []string{"Guild Boss"},
+	}, s.SyncGuildBosses)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "sync-all-guild-bosses",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/bosses/sync",
+		Summary:     "Add newly released global bosses and categories to every guild",
+		Tags:        []string{"Guild Boss"},
+	}, s.SyncAllGuildBosses)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-guild-categories",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/guilds/{guild_id}/categories",
+		Summary:     "Get the guild's categories with their enabled flag and ordering",
+		Tags:        []string{"Guild Boss"},
+	}, s.GetGuildCategories)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "update-guild-categories",
+		Method:      http.MethodPut,
+		Path:        "/api/v1/guilds/{guild_id}/categories",
+		Summary:     "Enable, disable or reorder the guild's categories",
+		Tags:        []string{"Guild Boss"},
+	}, s.UpdateGuildCategories)
+}
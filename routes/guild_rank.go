@@ -40,4 +40,20 @@ func RegisterGuildRankRoutes(api huma.API, s *handlers.Server) {
 		Summary:     "Delete a guild rank tier",
 		Tags:        []string{"Guild Rank"},
 	}, s.DeleteGuildRank)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-rank-assignments",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/guilds/{guild_id}/ranks/assignments",
+		Summary:     "Get the rank role every user should hold",
+		Tags:        []string{"Guild Rank"},
+	}, s.GetRankAssignments)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "diff-rank-assignments",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/guilds/{guild_id}/ranks/assignments",
+		Summary:     "Diff members' current roles against their rank roles",
+		Tags:        []string{"Guild Rank"},
+	}, s.DiffRankAssignments)
 }
@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"net/http"
+
+	"tectonic-api/handlers"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+func RegisterMultiplierRoutes(api huma.API, s *handlers.Server) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-multiplier-windows",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/guilds/{guild_id}/multipliers",
+		Summary:     "Get current and upcoming multiplier windows",
+		Tags:        []string{"Points"},
+	}, s.GetMultiplierWindows)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "create-multiplier-window",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/guilds/{guild_id}/multipliers",
+		Summary:     "Create a time-boxed points multiplier",
+		Tags:        []string{"Points"},
+	}, s.CreateMultiplierWindow)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "delete-multiplier-window",
+		Method:      http.MethodDelete,
+		Path:        "/api/v1/guilds/{guild_id}/multipliers/{window_id}",
+		Summary:     "Delete a multiplier window",
+		Tags:        []string{"Points"},
+	}, s.DeleteMultiplierWindow)
+}
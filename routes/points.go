@@ -33,6 +33,30 @@ func RegisterPointRoutes(api huma.API, s *handlers.Server) {
 		Tags:        []string{"Points"},
 	}, s.GetPointSources)
 
+	huma.Register(api, huma.Operation{
+		OperationID: "create-guild-point-source",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/guilds/{guild_id}/points",
+		Summary:     "Create a guild point source",
+		Tags:        []string{"Points"},
+	}, s.CreatePointSource)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "edit-guild-point-source",
+		Method:      http.MethodPatch,
+		Path:        "/api/v1/guilds/{guild_id}/points/{point_source}",
+		Summary:     "Rename or revalue a guild point source",
+		Tags:        []string{"Points"},
+	}, s.EditPointSource)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "delete-guild-point-source",
+		Method:      http.MethodDelete,
+		Path:        "/api/v1/guilds/{guild_id}/points/{point_source}",
+		Summary:     "Delete a guild point source",
+		Tags:        []string{"Points"},
+	}, s.DeletePointSource)
+
 	huma.Register(api, huma.Operation{
 		OperationID: "update-guild-point-source",
 		Method:      http.MethodPut,
@@ -40,4 +64,44 @@ func RegisterPointRoutes(api huma.API, s *handlers.Server) {
 		Summary:     "Update a guild point source",
 		Tags:        []string{"Points"},
 	}, s.UpdateGuildPointSource)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-points-history",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/guilds/{guild_id}/users/{user_id}/points/history",
+		Summary:     "Get a user's points history",
+		Tags:        []string{"Points"},
+	}, s.GetPointsHistory)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "verify-points",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/guilds/{guild_id}/points/verify",
+		Summary:     "List users whose points don't match their points history",
+		Tags:        []string{"Points"},
+	}, s.VerifyPoints)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "rebuild-points",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/guilds/{guild_id}/points/rebuild",
+		Summary:     "Rebuild user points from their points history",
+		Tags:        []string{"Points"},
+	}, s.RebuildPoints)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "revoke-point-transaction",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/guilds/{guild_id}/points/transactions/{transaction_id}/revoke",
+		Summary:     "Revoke a points transaction",
+		Tags:        []string{"Points"},
+	}, s.RevokePointTransaction)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "revoke-point-batch",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/guilds/{guild_id}/points/batches/{batch_id}/revoke",
+		Summary:     "Revoke every points transaction from one award",
+		Tags:        []string{"Points"},
+	}, s.RevokePointBatch)
 }
@@ -25,6 +25,86 @@ func RegisterRecordRoutes(api huma.API, s *handlers.Server) {
 		Tags:        []string{"Record"},
 	}, s.CreateRecord)
 
+	huma.Register(api, huma.Operation{
+		OperationID: "import-records",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/guilds/{guild_id}/records/import",
+		Summary:     "Bulk import records from CSV or JSON rows",
+		Tags:        []string{"Record"},
+	}, s.ImportRecords)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-boss-ranking",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/guilds/{guild_id}/records/{boss}",
+		Summary:     "Get the full ranking for a boss",
+		Tags:        []string{"Record"},
+	}, s.GetBossRanking)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-pending-records",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/guilds/{guild_id}/records/pending",
+		Summary:     "Get records waiting for moderator review",
+		Tags:        []string{"Record"},
+	}, s.GetPendingRecords)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-deleted-records",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/guilds/{guild_id}/records/deleted",
+		Summary:     "Get deleted records that can still be restored",
+		Tags:        []string{"Record"},
+	}, s.GetDeletedRecords)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-record",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/guilds/{guild_id}/records/id/{record_id}",
+		Summary:     "Get a record with its team and evidence",
+		Tags:        []string{"Record"},
+	}, s.GetRecord)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "update-record",
+		Method:      http.MethodPatch,
+		Path:        "/api/v1/guilds/{guild_id}/records/id/{record_id}",
+		Summary:     "Edit a record and re-rank it",
+		Tags:        []string{"Record"},
+	}, s.UpdateRecord)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "add-record-evidence",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/guilds/{guild_id}/records/id/{record_id}/evidence",
+		Summary:     "Attach evidence to a record",
+		Tags:        []string{"Record"},
+	}, s.AddRecordEvidence)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "approve-record",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/guilds/{guild_id}/records/id/{record_id}/approve",
+		Summary:     "Approve a pending record",
+		Tags:        []string{"Record"},
+	}, s.ApproveRecord)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "reject-record",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/guilds/{guild_id}/records/id/{record_id}/reject",
+		Summary:     "Reject a pending record",
+		Tags:        []string{"Record"},
+	}, s.RejectRecord)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "restore-record",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/guilds/{guild_id}/records/id/{record_id}/restore",
+		Summary:     "Restore a deleted record with its team",
+		Tags:        []string{"Record"},
+	}, s.RestoreRecord)
+
 	huma.Register(api, huma.Operation{
 		OperationID: "remove-record",
 		Method:      http.MethodDelete,
@@ -16,16 +16,22 @@ func AttachV1Routes(r chi.Router, s *handlers.Server) huma.API {
 	RegisterGuildRoutes(api, s)
 	RegisterUserRoutes(api, s)
 	RegisterRecordRoutes(api, s)
+	RegisterSeasonRoutes(api, s)
+	RegisterCustomBossRoutes(api, s)
+	RegisterGuildBossRoutes(api, s)
 	RegisterTeamRoutes(api, s)
 	RegisterEventRoutes(api, s)
 	RegisterPointRoutes(api, s)
+	RegisterMultiplierRoutes(api, s)
 	RegisterWomRoutes(api, s)
 	RegisterRsnRoutes(api, s)
 	RegisterLeaderboardRoutes(api, s)
 	RegisterAchievementRoutes(api, s)
 	RegisterCombatAchievementRoutes(api, s)
 	RegisterGuildRankRoutes(api, s)
+	RegisterAPIKeyRoutes(api, s)
 	RegisterMiscRoutes(api, s)
+	RegisterCatalogRoutes(api, s)
 
 	return api
 }
@@ -11,6 +11,7 @@ import (
 	"net/url"
 	"os"
 	"testing"
+	"time"
 
 	"tectonic-api/config"
 	"tectonic-api/database"
@@ -149,6 +150,44 @@ func TestRoutes(t *testing.T) {
 			StatusCode: 200,
 		},
 
+		// === Guild Bosses ===
+		{
+			Name:       "Get Guild Bosses",
+			Method:     "GET",
+			Path:       fmt.Sprintf("/api/v1/guilds/%s/bosses", v.GuildID),
+			StatusCode: 200,
+		},
+		{
+			Name:       "Get Guild Categories",
+			Method:     "GET",
+			Path:       fmt.Sprintf("/api/v1/guilds/%s/categories", v.GuildID),
+			StatusCode: 200,
+		},
+		{
+			Name:       "Sync Guild Bosses",
+			Method:     "POST",
+			Path:       fmt.Sprintf("/api/v1/guilds/%s/bosses/sync", v.GuildID),
+			StatusCode: 200,
+		},
+
+		// === API Keys ===
+		{
+			Name:   "Create API Key",
+			Method: "POST",
+			Path:   fmt.Sprintf("/api/v1/guilds/%s/keys", v.GuildID),
+			Body: models.CreateAPIKeyBody{
+				Name: "Integration tests",
+				Role: models.APIKeyRoleReadOnly,
+			},
+			StatusCode: 200,
+		},
+		{
+			Name:       "Get API Keys",
+			Method:     "GET",
+			Path:       fmt.Sprintf("/api/v1/guilds/%s/keys", v.GuildID),
+			StatusCode: 200,
+		},
+
 		// === Misc ===
 		{
 			Name:       "Get Bosses",
@@ -177,6 +216,12 @@ func TestRoutes(t *testing.T) {
 			Path:       fmt.Sprintf("/api/v1/guilds/%s/users/%s", v.GuildID, v.UserID),
 			StatusCode: 200,
 		},
+		{
+			Name:       "Get User (Include)",
+			Method:     "GET",
+			Path:       fmt.Sprintf("/api/v1/guilds/%s/users/%s?include=rsns,tier", v.GuildID, v.UserID),
+			StatusCode: 200,
+		},
 		{
 			Name:       "Get User (By WOM)",
 			Method:     "GET",
@@ -238,6 +283,68 @@ func TestRoutes(t *testing.T) {
 			Path:       fmt.Sprintf("/api/v1/guilds/%s/users/%s/points/custom/30", v.GuildID, v.UserID),
 			StatusCode: 200,
 		},
+		{
+			Name:       "Get Points History",
+			Method:     "GET",
+			Path:       fmt.Sprintf("/api/v1/guilds/%s/users/%s/points/history", v.GuildID, v.UserID),
+			StatusCode: 200,
+		},
+		{
+			Name:       "Verify Points",
+			Method:     "GET",
+			Path:       fmt.Sprintf("/api/v1/guilds/%s/points/verify", v.GuildID),
+			StatusCode: 200,
+		},
+		{
+			Name:   "Create Multiplier Window",
+			Method: "POST",
+			Path:   fmt.Sprintf("/api/v1/guilds/%s/multipliers", v.GuildID),
+			Body: models.CreateMultiplierWindowBody{
+				Name:       "Double points weekend",
+				Multiplier: 2,
+				StartsAt:   time.Now(),
+				EndsAt:     time.Now().Add(48 * time.Hour),
+			},
+			StatusCode: 200,
+		},
+		{
+			Name:       "Get Multiplier Windows",
+			Method:     "GET",
+			Path:       fmt.Sprintf("/api/v1/guilds/%s/multipliers", v.GuildID),
+			StatusCode: 200,
+		},
+		{
+			Name:   "Create Point Source",
+			Method: "POST",
+			Path:   fmt.Sprintf("/api/v1/guilds/%s/points", v.GuildID),
+			Body: models.CreatePointSourceBody{
+				Source: "mentoring",
+				Name:   "Mentoring",
+				Points: 5,
+			},
+			StatusCode: 200,
+		},
+		{
+			Name:   "Rename Point Source",
+			Method: "PATCH",
+			Path:   fmt.Sprintf("/api/v1/guilds/%s/points/mentoring", v.GuildID),
+			Body: models.UpdatePointSourceBody{
+				Name: utils.Ptr("Mentoring session"),
+			},
+			StatusCode: 200,
+		},
+		{
+			Name:       "Delete Point Source",
+			Method:     "DELETE",
+			Path:       fmt.Sprintf("/api/v1/guilds/%s/points/mentoring", v.GuildID),
+			StatusCode: 200,
+		},
+		{
+			Name:       "Delete Point Source (In Use)",
+			Method:     "DELETE",
+			Path:       fmt.Sprintf("/api/v1/guilds/%s/points/combat_achievement_low", v.GuildID),
+			StatusCode: 409,
+		},
 		{
 			Name:       "Get Point Sources",
 			Method:     "GET",
@@ -245,6 +352,25 @@ func TestRoutes(t *testing.T) {
 			StatusCode: 200,
 		},
 
+		// === Guild Ranks ===
+		{
+			Name:       "Get Rank Assignments",
+			Method:     "GET",
+			Path:       fmt.Sprintf("/api/v1/guilds/%s/ranks/assignments", v.GuildID),
+			StatusCode: 200,
+		},
+		{
+			Name:   "Diff Rank Assignments",
+			Method: "POST",
+			Path:   fmt.Sprintf("/api/v1/guilds/%s/ranks/assignments", v.GuildID),
+			Body: models.RankAssignmentsBody{
+				Members: []models.MemberRoles{
+					{UserID: models.DiscordSnowflake(v.UserID), RoleIDs: []models.DiscordSnowflake{}},
+				},
+			},
+			StatusCode: 200,
+		},
+
 		// === Leaderboard ===
 		{
 			Name:       "Get Leaderboard",
@@ -333,15 +459,70 @@ func TestRoutes(t *testing.T) {
 				Value:    rand.Intn(100000) + 1,
 				BossName: "vardorvis",
 				UserIDs:  []models.DiscordSnowflake{models.DiscordSnowflake(v.UserID)},
+				Evidence: []models.InputEvidence{{
+					Kind:        "screenshot",
+					URL:         "https://i.imgur.com/vardorvis.png",
+					SubmittedBy: models.DiscordSnowflake(v.UserID),
+				}},
 			},
 			StatusCode: 200,
 		},
+		{
+			Name:   "Create Record (Time String)",
+			Method: "POST",
+			Path:   fmt.Sprintf("/api/v1/guilds/%s/records", v.GuildID),
+			Body: models.InputRecord{
+				Time:     "1:23.40",
+				BossName: "vardorvis",
+				UserIDs:  []models.DiscordSnowflake{models.DiscordSnowflake(v.UserID)},
+			},
+			StatusCode: 200,
+		},
+		{
+			Name:   "Create Record (Malformed Time)",
+			Method: "POST",
+			Path:   fmt.Sprintf("/api/v1/guilds/%s/records", v.GuildID),
+			Body: models.InputRecord{
+				Time:     "1:2x.40",
+				BossName: "vardorvis",
+				UserIDs:  []models.DiscordSnowflake{models.DiscordSnowflake(v.UserID)},
+			},
+			StatusCode: 400,
+		},
 		{
 			Name:       "Get Guild Records",
 			Method:     "GET",
 			Path:       fmt.Sprintf("/api/v1/guilds/%s/records", v.GuildID),
 			StatusCode: 200,
 		},
+		{
+			Name:       "Update Record (Not Found)",
+			Method:     "PATCH",
+			Path:       fmt.Sprintf("/api/v1/guilds/%s/records/id/2147483647", v.GuildID),
+			Body:       models.UpdateRecordBody{Value: utils.Ptr(1234)},
+			StatusCode: 404,
+		},
+		{
+			Name:   "Import Records (Dry Run)",
+			Method: "POST",
+			Path:   fmt.Sprintf("/api/v1/guilds/%s/records/import?dry_run=true", v.GuildID),
+			Body: models.ImportRecordsBody{
+				CSV: fmt.Sprintf("boss,value,date,participants\nvardorvis,1:23.40,2024-05-01,%s\n", v.UserID),
+			},
+			StatusCode: 200,
+		},
+		{
+			Name:       "Get Boss Ranking",
+			Method:     "GET",
+			Path:       fmt.Sprintf("/api/v1/guilds/%s/records/vardorvis?limit=10", v.GuildID),
+			StatusCode: 200,
+		},
+		{
+			Name:       "Get Pending Records",
+			Method:     "GET",
+			Path:       fmt.Sprintf("/api/v1/guilds/%s/records/pending", v.GuildID),
+			StatusCode: 200,
+		},
 
 		// === Delete Users (all variations) ===
 		{
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"net/http"
+
+	"tectonic-api/handlers"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+func RegisterSeasonRoutes(api huma.API, s *handlers.Server) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-seasons",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/guilds/{guild_id}/seasons",
+		Summary:     "Get the guild's current and past seasons",
+		Tags:        []string{"Season"},
+	}, s.GetSeasons)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "start-season",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/guilds/{guild_id}/seasons",
+		Summary:     "Close the current season and open a new one",
+		Tags:        []string{"Season"},
+	}, s.StartSeason)
+}
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+const apiKeyPrefix = "tec_"
+
+// GenerateAPIKey creates a new random API key. Only the hash is meant to be
+// stored, the prefix is kept so keys can be told apart when listing them.
+func GenerateAPIKey() (key string, prefix string, hash string, err error) {
+	b := make([]byte, 24)
+	if _, err = rand.Read(b); err != nil {
+		return "", "", "", err
+	}
+
+	key = apiKeyPrefix + hex.EncodeToString(b)
+	prefix = key[:len(apiKeyPrefix)+6]
+	hash = HashAPIKey(key)
+
+	return key, prefix, hash, nil
+}
+
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -6,3 +6,7 @@ func DerefOr[T any](p *T, fallback T) T {
 	}
 	return fallback
 }
+
+func Ptr[T any](v T) *T {
+	return &v
+}
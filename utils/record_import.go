@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"tectonic-api/models"
+)
+
+// ParseRecordCSV reads rows in the form boss,value,date,participants where
+// participants are separated by semicolons. A leading header row is skipped.
+func ParseRecordCSV(data string) ([]models.ImportRecordRow, error) {
+	r := csv.NewReader(strings.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	rows := make([]models.ImportRecordRow, 0)
+	for line := 1; ; line++ {
+		fields, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if line == 1 && strings.EqualFold(strings.TrimSpace(fields[0]), "boss") {
+			continue
+		}
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("line %d: expected 4 columns (boss,value,date,participants), got %d", line, len(fields))
+		}
+
+		participants := make([]string, 0)
+		for _, p := range strings.Split(fields[3], ";") {
+			if p = strings.TrimSpace(p); p != "" {
+				participants = append(participants, p)
+			}
+		}
+
+		rows = append(rows, models.ImportRecordRow{
+			Boss:         strings.TrimSpace(fields[0]),
+			Value:        strings.TrimSpace(fields[1]),
+			Date:         strings.TrimSpace(fields[2]),
+			Participants: participants,
+		})
+	}
+	return rows, nil
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"tectonic-api/models"
+)
+
+func TestParseRecordCSV(t *testing.T) {
+	data := "boss,value,date,participants\n" +
+		"vardorvis,1:23.40,2024-05-01,Zezima\n" +
+		"cox_3, 25:10.20 ,,123456789012345678; Lynx Titan\n"
+
+	expected := []models.ImportRecordRow{
+		{Boss: "vardorvis", Value: "1:23.40", Date: "2024-05-01", Participants: []string{"Zezima"}},
+		{Boss: "cox_3", Value: "25:10.20", Date: "", Participants: []string{"123456789012345678", "Lynx Titan"}},
+	}
+
+	rows, err := ParseRecordCSV(data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Expected rows: %v, got: %v", expected, rows)
+	}
+}
+
+func TestParseRecordCSVWithoutHeader(t *testing.T) {
+	rows, err := ParseRecordCSV("vardorvis,139,,Zezima\n")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Value != "139" {
+		t.Errorf("Expected a single row with value 139, got: %v", rows)
+	}
+}
+
+func TestParseRecordCSVWrongColumns(t *testing.T) {
+	if _, err := ParseRecordCSV("vardorvis,139,Zezima\n"); err == nil {
+		t.Errorf("Expected an error for a row with missing columns")
+	}
+}
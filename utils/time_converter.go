@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 )
@@ -13,46 +14,91 @@ const (
 	gametickInMilliseconds = 600
 )
 
-func TimeToMs(time string) int {
+// TimeToMs parses times in the form [[hh:]mm:]ss[.fff] into milliseconds
+func TimeToMs(time string) (int, error) {
 	tc := strings.Split(time, ":")
-	nc := len(tc)
+	if len(tc) > 3 {
+		return 0, fmt.Errorf("invalid time %q, expected [[hh:]mm:]ss[.ff]", time)
+	}
+
+	// Only the seconds component may carry a fraction
+	last := len(tc) - 1
+	s_and_ms := strings.Split(tc[last], ".")
+	if len(s_and_ms) > 2 {
+		return 0, fmt.Errorf("invalid time %q, expected [[hh:]mm:]ss[.ff]", time)
+	}
+	tc[last] = s_and_ms[0]
 
-	ms := 0
-	if nc == 3 {
-		h, _ := strconv.Atoi(tc[0])
-		m, _ := strconv.Atoi(tc[1])
-		s, _ := strconv.Atoi(tc[2])
-		ms = h*hourInMilliseconds + m*minuteInMilliseconds + s*secondInMilliseconds
-	} else if nc == 2 {
-		m, _ := strconv.Atoi(tc[0])
-		s_and_ms := strings.Split(tc[1], ".")
-		s, _ := strconv.Atoi(s_and_ms[0])
-		ms_str := "0"
-		if len(s_and_ms) > 1 {
-			ms_str = s_and_ms[1]
-			ms_str = ms_str + strings.Repeat("0", 3-len(ms_str))
+	parts := make([]int, len(tc))
+	for i, c := range tc {
+		n, err := parseTimeComponent(c)
+		if err != nil {
+			return 0, fmt.Errorf("invalid time %q, expected [[hh:]mm:]ss[.ff]", time)
+		}
+		// The leading component is unbounded, keep it from overflowing
+		if i == 0 && n > math.MaxInt32 {
+			return 0, fmt.Errorf("invalid time %q, time is too long", time)
+		}
+		// Anything below the leading component has to wrap at 60
+		if i > 0 && n >= 60 {
+			return 0, fmt.Errorf("invalid time %q, minutes and seconds must be below 60", time)
+		}
+		parts[i] = n
+	}
+
+	ms_part := 0
+	if len(s_and_ms) == 2 {
+		ms_str := s_and_ms[1]
+		if len(ms_str) == 0 || len(ms_str) > 3 {
+			return 0, fmt.Errorf("invalid time %q, fraction must have 1-3 digits", time)
+		}
+		ms_str = ms_str + strings.Repeat("0", 3-len(ms_str))
+		n, err := parseTimeComponent(ms_str)
+		if err != nil {
+			return 0, fmt.Errorf("invalid time %q, expected [[hh:]mm:]ss[.ff]", time)
 		}
-		ms_part, _ := strconv.Atoi(ms_str)
-		ms = m*minuteInMilliseconds + s*secondInMilliseconds + ms_part
-	} else if nc == 1 {
-		ms_str := tc[0]
-		ms, _ = strconv.Atoi(ms_str)
+		ms_part = n
 	}
 
-	return ms
+	ms := ms_part
+	units := []int{secondInMilliseconds, minuteInMilliseconds, hourInMilliseconds}
+	for i := range parts {
+		ms += parts[last-i] * units[i]
+	}
+	return ms, nil
 }
 
-func TimeToTicks(time string) int {
-	totalms := TimeToMs(time)
+func parseTimeComponent(c string) (int, error) {
+	if c == "" {
+		return 0, fmt.Errorf("empty time component")
+	}
+	for _, r := range c {
+		if r < '0' || r > '9' {
+			return 0, fmt.Errorf("time component %q is not numeric", c)
+		}
+	}
+	return strconv.Atoi(c)
+}
+
+// TimeToTicks parses a time and rounds it up to whole game ticks
+func TimeToTicks(time string) (int, error) {
+	totalms, err := TimeToMs(time)
+	if err != nil {
+		return 0, err
+	}
 	ticks := totalms / gametickInMilliseconds
 	if totalms%gametickInMilliseconds != 0 {
 		ticks++
 	}
-	return ticks
+	return ticks, nil
 }
 
 func TicksToTime(ticks int) string {
-	ms := ticks * gametickInMilliseconds
+	return MsToTime(ticks * gametickInMilliseconds)
+}
+
+// MsToTime renders milliseconds as [[hh:]mm:]ss.ff
+func MsToTime(ms int) string {
 	h := ms / hourInMilliseconds
 	ms %= hourInMilliseconds
 	m := ms / minuteInMilliseconds
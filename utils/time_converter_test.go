@@ -21,6 +21,8 @@ func TestTimeConversions(t *testing.T) {
 		{"06:17.40", 377400, "06:17.40", 629},
 		{"14:32.40", 872400, "14:32.40", 1454},
 		{"00:34.80", 34800, "34.80", 58},
+		{"01:02:03.00", 3723000, "01:02:03.00", 6205},
+		{"34.80", 34800, "34.80", 58},
 	}
 
 	for _, tc := range testCases {
@@ -31,14 +33,20 @@ func TestTimeConversions(t *testing.T) {
 }
 
 func testTimeToMs(t *testing.T, tc TimeTest) {
-	result := TimeToMs(tc.input.(string))
+	result, err := TimeToMs(tc.input.(string))
+	if err != nil {
+		t.Errorf("TimeToMs(%v): unexpected error %v", tc.input, err)
+	}
 	if result != tc.expectedMs {
 		t.Errorf("TimeToMs(%v): expected %v, got %v", tc.input, tc.expectedMs, result)
 	}
 }
 
 func testTimeToTicks(t *testing.T, tc TimeTest) {
-	result := TimeToTicks(tc.input.(string))
+	result, err := TimeToTicks(tc.input.(string))
+	if err != nil {
+		t.Errorf("TimeToTicks(%v): unexpected error %v", tc.input, err)
+	}
 	if result != tc.expectedTicks {
 		t.Errorf("TimeToTicks(%v): expected %v, got %v", tc.input, tc.expectedTicks, result)
 	}
@@ -50,3 +58,25 @@ func testTicksToTime(t *testing.T, tc TimeTest) {
 		t.Errorf("TicksToTime(%v): expected %v, got %v", tc.expectedTicks, tc.expectedTime, result)
 	}
 }
+
+func TestTimeToMsInvalid(t *testing.T) {
+	inputs := []string{
+		"",
+		"abc",
+		"1:2:3:4",
+		"01:60.00",
+		"01:61:00",
+		"01:23.",
+		"01:23.4567",
+		"01:23.4.5",
+		"-01:23",
+		"01::23",
+		"1:23,40",
+	}
+
+	for _, input := range inputs {
+		if ms, err := TimeToMs(input); err == nil {
+			t.Errorf("TimeToMs(%q): expected error, got %v", input, ms)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// MaxValue is the largest value a record can store, values are kept in an
+// integer column
+const MaxValue = math.MaxInt32
+
+// Display formats a value type can use, they mirror the
+// value_types_display_format_check constraint
+const (
+	DisplayFormatTicksAsTime = "ticks_as_time"
+	DisplayFormatInteger     = "integer"
+	DisplayFormatPercentage  = "percentage"
+)
+
+// ValueFormat describes how the stored values of a value type are shown and
+// which values are accepted. Values are stored as whole steps, the step is
+// what one stored unit is worth: milliseconds for ticks_as_time, hundredths
+// of a percent for percentage and the plain number for integer.
+type ValueFormat struct {
+	DisplayFormat string
+	Unit          string
+	Step          int
+	Min           *int
+	Max           *int
+}
+
+func (f ValueFormat) step() int {
+	if f.Step <= 0 {
+		return 1
+	}
+	return f.Step
+}
+
+// FormatValue renders a stored value for display, an unknown value type
+// falls back to the plain number
+func FormatValue(f ValueFormat, value int) string {
+	switch f.DisplayFormat {
+	case DisplayFormatTicksAsTime:
+		return MsToTime(value * f.step())
+	case DisplayFormatPercentage:
+		hundredths := value * f.step()
+		if f.step()%100 == 0 {
+			return fmt.Sprintf("%d%%", hundredths/100)
+		}
+		return fmt.Sprintf("%d.%02d%%", hundredths/100, hundredths%100)
+	default:
+		n := strconv.Itoa(value * f.step())
+		if f.Unit != "" {
+			return n + " " + f.Unit
+		}
+		return n
+	}
+}
+
+// ParseTime converts a human readable time into stored steps, rounding up to
+// the next whole step like the game does with ticks
+func (f ValueFormat) ParseTime(t string) (int, error) {
+	if f.DisplayFormat != DisplayFormatTicksAsTime {
+		return 0, errors.New("time can only be submitted for time based bosses")
+	}
+
+	ms, err := TimeToMs(t)
+	if err != nil {
+		return 0, err
+	}
+	steps := ms / f.step()
+	if ms%f.step() != 0 {
+		steps++
+	}
+	if steps <= 0 {
+		return 0, errors.New("time must be greater than zero")
+	}
+	if steps > MaxValue {
+		return 0, errors.New("time is too long")
+	}
+	return steps, nil
+}
+
+// Validate checks a stored value against the value type's bounds, values
+// that don't fit the records column are rejected even without bounds
+func (f ValueFormat) Validate(value int) error {
+	if value > MaxValue {
+		return fmt.Errorf("value must be at most %d", MaxValue)
+	}
+	if f.Min != nil && value < *f.Min {
+		return fmt.Errorf("value must be at least %s", FormatValue(f, *f.Min))
+	}
+	if f.Max != nil && value > *f.Max {
+		return fmt.Errorf("value must be at most %s", FormatValue(f, *f.Max))
+	}
+	return nil
+}
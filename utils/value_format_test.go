@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"testing"
+)
+
+var (
+	timeFormat    = ValueFormat{DisplayFormat: DisplayFormatTicksAsTime, Unit: "ticks", Step: 600}
+	depthFormat   = ValueFormat{DisplayFormat: DisplayFormatInteger, Step: 1}
+	killFormat    = ValueFormat{DisplayFormat: DisplayFormatInteger, Unit: "kc", Step: 1}
+	percentFormat = ValueFormat{DisplayFormat: DisplayFormatPercentage, Step: 1, Min: ptr(0), Max: ptr(10000)}
+)
+
+func ptr(n int) *int {
+	return &n
+}
+
+func TestFormatValue(t *testing.T) {
+	testCases := []struct {
+		format   ValueFormat
+		value    int
+		expected string
+	}{
+		{timeFormat, 139, "01:23.40"},
+		{depthFormat, 42, "42"},
+		{killFormat, 1500, "1500 kc"},
+		{percentFormat, 4512, "45.12%"},
+		{percentFormat, 5, "0.05%"},
+		{ValueFormat{DisplayFormat: DisplayFormatPercentage, Step: 100}, 45, "45%"},
+		{ValueFormat{}, 7, "7"},
+	}
+
+	for _, tc := range testCases {
+		if result := FormatValue(tc.format, tc.value); result != tc.expected {
+			t.Errorf("FormatValue(%+v, %v): expected %v, got %v", tc.format, tc.value, tc.expected, result)
+		}
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	steps, err := timeFormat.ParseTime("01:23.3")
+	if err != nil || steps != 139 {
+		t.Errorf("ParseTime(01:23.3): expected 139, got %v (%v)", steps, err)
+	}
+
+	if _, err := timeFormat.ParseTime("00.00"); err == nil {
+		t.Error("ParseTime(00.00): expected error for a zero time")
+	}
+	if _, err := killFormat.ParseTime("01:23.3"); err == nil {
+		t.Error("ParseTime on an integer value type: expected error")
+	}
+	if _, err := timeFormat.ParseTime("3579139:25:00"); err == nil {
+		t.Error("ParseTime(3579139:25:00): expected error for a time that overflows int32")
+	}
+	if _, err := timeFormat.ParseTime("99999999999999:00"); err == nil {
+		t.Error("ParseTime(99999999999999:00): expected error for a time that overflows int64")
+	}
+}
+
+func TestValidateValue(t *testing.T) {
+	if err := percentFormat.Validate(10000); err != nil {
+		t.Errorf("Validate(10000): unexpected error %v", err)
+	}
+	if err := percentFormat.Validate(10001); err == nil || err.Error() != "value must be at most 100.00%" {
+		t.Errorf("Validate(10001): expected upper bound error, got %v", err)
+	}
+	if err := depthFormat.Validate(1 << 20); err != nil {
+		t.Errorf("Validate without bounds: unexpected error %v", err)
+	}
+	if err := depthFormat.Validate(MaxValue + 1); err == nil {
+		t.Error("Validate(MaxValue + 1): expected error without bounds")
+	}
+}